@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"file-converter/internal/pool"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// inputFileRef est un fichier à convertir et son chemin relatif à la racine
+// de l'entrée, reproduit sous le dossier de sortie
+type inputFileRef struct {
+	Path  string
+	Rel   string
+	Stdin bool // Entrée standard; Path ne sert qu'à nommer la sortie
+}
+
+// collectInputs résout --input en liste de fichiers. batch indique que
+// l'entrée désignait un dossier ou un motif plutôt qu'un fichier unique.
+func collectInputs(pattern string, recursive bool) ([]inputFileRef, bool, error) {
+	if stat, err := os.Stat(pattern); err == nil {
+		if !stat.IsDir() {
+			return []inputFileRef{{Path: pattern, Rel: filepath.Base(pattern)}}, false, nil
+		}
+		files, err := walkInputs(pattern, "", recursive)
+		if err != nil {
+			return nil, true, err
+		}
+		if len(files) == 0 {
+			return nil, true, fmt.Errorf("aucun fichier à convertir dans %s", pattern)
+		}
+		return files, true, nil
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return nil, false, fmt.Errorf("le fichier %s n'existe pas", pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, true, fmt.Errorf("motif invalide %s: %v", pattern, err)
+	}
+
+	// La racine est la partie du motif sans caractère spécial
+	root := globRoot(pattern)
+	var files []inputFileRef
+	if recursive {
+		// En mode récursif, le dernier élément du motif filtre les noms de fichiers
+		var err error
+		files, err = walkInputs(root, filepath.Base(pattern), true)
+		if err != nil {
+			return nil, true, err
+		}
+	} else {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, true, fmt.Errorf("motif invalide %s: %v", pattern, err)
+		}
+		for _, match := range matches {
+			if stat, err := os.Stat(match); err != nil || stat.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				rel = filepath.Base(match)
+			}
+			files = append(files, inputFileRef{Path: match, Rel: rel})
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, true, fmt.Errorf("aucun fichier ne correspond à %s", pattern)
+	}
+	return files, true, nil
+}
+
+// walkInputs liste les fichiers de root, éventuellement filtrés par un motif de nom
+func walkInputs(root, namePattern string, recursive bool) ([]inputFileRef, error) {
+	var files []inputFileRef
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") || !d.Type().IsRegular() {
+			return nil
+		}
+		if namePattern != "" {
+			if ok, _ := filepath.Match(namePattern, d.Name()); !ok {
+				return nil
+			}
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, inputFileRef{Path: path, Rel: rel})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parcours de %s: %v", root, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Rel < files[j].Rel })
+	return files, nil
+}
+
+// globRoot retourne le dossier le plus profond du motif qui ne contient pas de caractère spécial
+func globRoot(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for strings.ContainsAny(dir, "*?[") {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// runBatch convertit tous les fichiers sur un pool de workers et affiche la
+// progression dans l'ordre des fichiers, puis un récapitulatif.
+// Une erreur est retournée si au moins une conversion a échoué.
+func runBatch(ctx context.Context, inputs []inputFileRef, outputDir string) error {
+	if err := checkOutputCollisions(inputs, outputDir); err != nil {
+		return err
+	}
+
+	workers := pool.New(jobs)
+	var failures []conversionResult
+	done := 0
+
+	pool.Ordered(ctx, workers, len(inputs),
+		func(ctx context.Context, i int) conversionResult {
+			result := convertFile(ctx, inputs[i], outputDir)
+			// Conversion interrompue par l'arrêt au premier échec
+			if result.Err != nil && ctx.Err() != nil {
+				result.Cancelled = true
+			}
+			return result
+		},
+		func(result conversionResult) bool {
+			return failFast && result.Err != nil
+		},
+		func(i int, result conversionResult) {
+			in := inputs[i]
+			if result.Cancelled {
+				fmt.Printf("[%d/%d] - %s : annulé\n", i+1, len(inputs), in.Path)
+				return
+			}
+			done++
+			if result.Err != nil {
+				failures = append(failures, result)
+				fmt.Printf("[%d/%d] ✗ %s : %v\n", i+1, len(inputs), in.Path, result.Err)
+				return
+			}
+			fmt.Printf("[%d/%d] ✓ %s → %s\n", i+1, len(inputs), in.Path, result.Output)
+		})
+
+	return printSummary(len(inputs), done, failures)
+}
+
+// checkOutputCollisions calcule le fichier produit par chaque entrée avant
+// de lancer les conversions, et échoue si plusieurs entrées produiraient le
+// même fichier (in/a.json et in/a.xml vers a.csv): la dernière écraserait
+// les autres, ou les écritures simultanées se mélangeraient.
+func checkOutputCollisions(inputs []inputFileRef, outputDir string) error {
+	var outputs []string
+	sources := make(map[string][]string)
+	for _, in := range inputs {
+		output, err := plannedOutput(in, outputDir)
+		if err != nil {
+			// L'erreur sera signalée par la conversion du fichier
+			continue
+		}
+		if _, ok := sources[output]; !ok {
+			outputs = append(outputs, output)
+		}
+		sources[output] = append(sources[output], in.Path)
+	}
+
+	var conflicts []string
+	for _, output := range outputs {
+		if len(sources[output]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("  - %s : %s", output, strings.Join(sources[output], ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("plusieurs fichiers d'entrée produisent le même fichier de sortie:\n%s", strings.Join(conflicts, "\n"))
+	}
+	return nil
+}
+
+// plannedOutput retourne le fichier que produira la conversion de in
+func plannedOutput(in inputFileRef, outputDir string) (string, error) {
+	file, err := os.Open(in.Path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, _, pipeline, err := planConversion(file, in)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(outputPath(in, outputDir, pipeline)), nil
+}
+
+// printSummary affiche le bilan d'un traitement par lot
+func printSummary(total, processed int, failures []conversionResult) error {
+	fmt.Printf("\nRésumé : %d fichier(s), %d réussite(s), %d échec(s)",
+		total, processed-len(failures), len(failures))
+	if skipped := total - processed; skipped > 0 {
+		fmt.Printf(", %d non traité(s)", skipped)
+	}
+	fmt.Println()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	fmt.Println("Échecs :")
+	for _, failure := range failures {
+		fmt.Printf("  - %s : %v\n", failure.Input.Path, failure.Err)
+	}
+	return fmt.Errorf("%d conversion(s) en échec", len(failures))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckOutputCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		format   string
+		conflict []string // Entrées en conflit, dans l'ordre
+	}{
+		{
+			name:   "noms distincts",
+			files:  map[string]string{"a.json": `[{"a":1}]`, "b.json": `[{"b":1}]`},
+			format: "csv",
+		},
+		{
+			name:     "même nom, formats différents",
+			files:    map[string]string{"a.json": `[{"a":1}]`, "a.xml": `<root><item><a>1</a></item></root>`},
+			format:   "csv",
+			conflict: []string{"a.json", "a.xml"},
+		},
+		{
+			name:     "fichier compressé",
+			files:    map[string]string{"a.csv": "a\n1\n", "a.json": `[{"a":1}]`},
+			format:   "csv+gzip",
+			conflict: []string{"a.csv", "a.json"},
+		},
+		{
+			name:   "sous-dossiers",
+			files:  map[string]string{"a.json": `[{"a":1}]`, "sub/a.xml": `<root><item><a>1</a></item></root>`},
+			format: "csv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(format string) { outputFormat = format }(outputFormat)
+			outputFormat = tt.format
+
+			dir := t.TempDir()
+			in := filepath.Join(dir, "in")
+			for name, content := range tt.files {
+				path := filepath.Join(in, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			inputs, _, err := collectInputs(in, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkOutputCollisions(inputs, filepath.Join(dir, "out"))
+			if len(tt.conflict) == 0 {
+				if err != nil {
+					t.Fatalf("conflit inattendu: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("conflit attendu")
+			}
+			paths := make([]string, len(tt.conflict))
+			for i, name := range tt.conflict {
+				paths[i] = filepath.Join(in, name)
+			}
+			if want := strings.Join(paths, ", "); !strings.Contains(err.Error(), want) {
+				t.Errorf("erreur = %v, entrées attendues %s", err, want)
+			}
+		})
+	}
+}
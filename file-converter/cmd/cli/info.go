@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"file-converter/internal/converter"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	infoFile string
+	infoJSON bool
+)
+
+var infoCmd = &cobra.Command{
+	Use:     "info",
+	Aliases: []string{"detect"},
+	Short:   "Affiche le format et les caractéristiques d'un fichier",
+	Long: `Analyse le contenu d'un fichier pour en déterminer le format, le type MIME,
+la taille et des détails propres au format (colonnes CSV, structure JSON,
+dimensions d'image, contenu d'archive...).
+Exemple: converter info -i data.csv.gz --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := os.Open(infoFile)
+		if err != nil {
+			return fmt.Errorf("le fichier %s n'existe pas", infoFile)
+		}
+		defer file.Close()
+
+		info, err := converter.Inspect(file, infoFile)
+		if err != nil {
+			return fmt.Errorf("erreur lors de l'analyse: %v", err)
+		}
+
+		if infoJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(info)
+		}
+		printInfo(info)
+		return nil
+	},
+}
+
+// printInfo affiche les informations d'un fichier sous forme lisible
+func printInfo(info *converter.FileInfo) {
+	format := info.Format
+	if format == "" {
+		format = "inconnu"
+	}
+	if len(info.Layers) > 1 {
+		format = strings.Join(info.Layers, " → ")
+	}
+
+	fmt.Printf("Fichier   : %s\n", info.Name)
+	fmt.Printf("Format    : %s\n", format)
+	if info.MIME != "" {
+		fmt.Printf("Type MIME : %s\n", info.MIME)
+	}
+	if info.Confidence > 0 {
+		fmt.Printf("Confiance : %.0f%%\n", info.Confidence*100)
+	} else if info.Format != "" {
+		fmt.Println("Confiance : déduit de l'extension")
+	}
+	fmt.Printf("Taille    : %d octets\n", info.Size)
+	if info.Encoding != "" {
+		fmt.Printf("Encodage  : %s\n", info.Encoding)
+	}
+
+	if archive := info.Archive; archive != nil {
+		fmt.Printf("\nArchive (%d entrée(s)) :\n", len(archive.Entries))
+		for _, entry := range archive.Entries {
+			name := entry.Name
+			if name == "" {
+				name = "(sans nom)"
+			}
+			if entry.CompressedSize > 0 {
+				fmt.Printf("  - %s (%d octets, %d compressés)\n", name, entry.Size, entry.CompressedSize)
+			} else {
+				fmt.Printf("  - %s (%d octets)\n", name, entry.Size)
+			}
+		}
+		if archive.Ratio > 0 {
+			fmt.Printf("  Taille décompressée  : %d octets\n", archive.UncompressedSize)
+			fmt.Printf("  Ratio de compression : %.2f\n", archive.Ratio)
+		}
+	}
+
+	if table := info.Table; table != nil {
+		fmt.Println("\nTableau :")
+		fmt.Printf("  Colonnes (%d) : %s\n", len(table.Columns), strings.Join(table.Columns, ", "))
+		fmt.Printf("  Lignes        : %d\n", table.Rows)
+		fmt.Printf("  Séparateur    : %q\n", table.Delimiter)
+		fmt.Printf("  Citation      : %q\n", table.Quote)
+		if !table.Header {
+			fmt.Println("  En-tête       : absent")
+		}
+		if table.CRLF {
+			fmt.Println("  Fins de ligne : CRLF")
+		}
+	}
+
+	if j := info.JSON; j != nil {
+		fmt.Println("\nJSON :")
+		fmt.Printf("  Type     : %s\n", j.Type)
+		if j.Type == "array" {
+			fmt.Printf("  Éléments : %d\n", j.Length)
+		}
+		if len(j.Keys) > 0 {
+			fmt.Printf("  Clés     : %s\n", strings.Join(j.Keys, ", "))
+		}
+	}
+
+	if x := info.XML; x != nil {
+		fmt.Println("\nXML :")
+		fmt.Printf("  Racine   : <%s>\n", x.Root)
+		fmt.Printf("  Enfants  : %d\n", x.Children)
+		if len(x.Elements) > 0 {
+			fmt.Printf("  Éléments : %s\n", strings.Join(x.Elements, ", "))
+		}
+	}
+
+	if t := info.Text; t != nil {
+		fmt.Printf("\nTexte :\n  Lignes : %d\n  Forme  : %s\n", t.Lines, t.Layout)
+	}
+
+	if img := info.Image; img != nil {
+		fmt.Println("\nImage :")
+		fmt.Printf("  Dimensions : %dx%d\n", img.Width, img.Height)
+		fmt.Printf("  Couleurs   : %s\n", img.ColorModel)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	infoCmd.Flags().StringVarP(&infoFile, "input", "i", "", "Fichier à analyser")
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Affiche le résultat en JSON")
+	infoCmd.MarkFlagRequired("input")
+}
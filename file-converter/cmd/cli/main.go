@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"file-converter/internal/converter"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +17,10 @@ var (
 	inputFile  string
 	outputFormat string
 	outputDir  string
+	recursive  bool
+	jobs       int
+	failFast   bool
+	inputFrom  string
 )
 
 var rootCmd = &cobra.Command{
@@ -26,87 +34,274 @@ var convertCmd = &cobra.Command{
 	Use:   "convert",
 	Short: "Convertit un fichier vers un autre format",
 	Long: `Convertit un fichier d'entrée vers le format spécifié.
-Exemple: converter convert -i input.json -f csv -o result`,
+Plusieurs formats séparés par "+" enchaînent les conversions sans fichier intermédiaire.
+L'entrée peut aussi être un dossier ou un motif ("exports/*.json"): l'arborescence
+source est alors reproduite dans le dossier de sortie.
+"-" comme entrée ou comme sortie désigne l'entrée ou la sortie standard.
+Exemple: converter convert -i input.json -f csv -o result
+         converter convert -i input.xml -f csv+gzip -o result
+         converter convert -i exports/ -r -f csv -o result
+         curl -s https://example.com/data.csv | converter convert -i - -f json -o - | jq`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Vérifier que le fichier d'entrée existe
-		if _, err := os.Stat(inputFile); os.IsNotExist(err) {
-			return fmt.Errorf("le fichier %s n'existe pas", inputFile)
+		toStdout := outputDir == stdioName
+
+		// Valider le format d'entrée imposé et les options de format
+		if inputFrom != "" {
+			if _, err := converter.ParseLayers(inputFrom); err != nil {
+				return err
+			}
+		}
+		if err := buildFormatOptions(); err != nil {
+			return err
+		}
+
+		// Lister les fichiers d'entrée (entrée standard, fichier unique, dossier ou motif)
+		inputs := []inputFileRef{stdinRef}
+		batch := false
+		if inputFile != stdioName {
+			var err error
+			inputs, batch, err = collectInputs(inputFile, recursive)
+			if err != nil {
+				return err
+			}
+		}
+		if batch && toStdout {
+			return fmt.Errorf("la sortie standard n'accepte qu'un seul fichier d'entrée")
 		}
 
 		// Créer le dossier de sortie s'il n'existe pas
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("impossible de créer le dossier de sortie: %v", err)
+		if !toStdout {
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return fmt.Errorf("impossible de créer le dossier de sortie: %v", err)
+			}
 		}
 
-		// Lire le fichier d'entrée
-		input, err := os.ReadFile(inputFile)
-		if err != nil {
-			return fmt.Errorf("erreur lors de la lecture du fichier: %v", err)
+		// Les arguments sont valides: les erreurs suivantes n'appellent pas l'aide
+		cmd.SilenceUsage = true
+
+		if !batch {
+			result := convertFile(cmd.Context(), inputs[0], outputDir)
+			if toStdout {
+				// Rien d'autre que le résultat ne doit apparaître sur la sortie standard
+				return result.Err
+			}
+			if result.Route != "" {
+				fmt.Printf("Chemin de conversion : %s\n", result.Route)
+			}
+			if result.Err != nil {
+				return result.Err
+			}
+			fmt.Printf("Conversion réussie ! Fichier sauvegardé : %s\n", result.Output)
+			return nil
 		}
 
-		// Sélectionner le convertisseur approprié
-		var conv converter.Converter
-		switch outputFormat {
-		case "json", "csv", "xml", "txt":
-			conv = &converter.TextConverter{}
-		case "jpeg", "png", "gif":
-			conv = &converter.ImageConverter{}
-		case "gzip", "gunzip":
-			conv = &converter.CompressConverter{}
-		default:
-			return fmt.Errorf("format non supporté: %s", outputFormat)
+		return runBatch(cmd.Context(), inputs, outputDir)
+	},
+}
+
+// conversionResult décrit le résultat de la conversion d'un fichier
+type conversionResult struct {
+	Input  inputFileRef
+	Output string
+	Route  string // Chemin suivi ("csv.gz → gunzip → xml")
+	Err    error
+
+	Cancelled bool // Conversion interrompue avant la fin
+}
+
+// convertFile convertit un fichier vers outputFormat dans outputDir,
+// en reproduisant le chemin relatif de l'entrée
+func convertFile(ctx context.Context, in inputFileRef, outputDir string) conversionResult {
+	if in.Stdin {
+		return convertReader(ctx, os.Stdin, in, outputDir)
+	}
+
+	// Ouvrir le fichier d'entrée
+	input, err := os.Open(in.Path)
+	if err != nil {
+		return conversionResult{Input: in, Err: fmt.Errorf("erreur lors de la lecture du fichier: %v", err)}
+	}
+	defer input.Close()
+
+	return convertReader(ctx, input, in, outputDir)
+}
+
+// convertReader convertit le contenu de r, nommé d'après in, vers outputFormat.
+// Le résultat est écrit dans outputDir, ou sur la sortie standard pour "-".
+func convertReader(ctx context.Context, r io.Reader, in inputFileRef, outputDir string) conversionResult {
+	result := conversionResult{Input: in}
+
+	layers, reader, pipeline, err := planConversion(r, in)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Route = fmt.Sprintf("%s → %s",
+		converter.DefaultRegistry.DescribeLayers(layers),
+		strings.ReplaceAll(pipeline.String(), converter.PipelineSeparator, " → "))
+	opts := formatOptions
+	opts.OutputFormat = pipeline.Output().Name
+
+	// Écrire directement sur la sortie standard
+	if outputDir == stdioName {
+		if err := pipeline.ConvertStream(ctx, reader, os.Stdout, opts); err != nil {
+			result.Err = fmt.Errorf("erreur lors de la conversion: %v", err)
+			return result
 		}
+		result.Output = stdioName
+		return result
+	}
+
+	// Générer le nom du fichier de sortie
+	outputFile := outputPath(in, outputDir, pipeline)
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		result.Err = fmt.Errorf("impossible de créer le dossier de sortie: %v", err)
+		return result
+	}
+	source := in.Path
+	if in.Stdin {
+		source = ""
+	}
 
-		// Convertir le fichier
-		result, err := conv.Convert(input, outputFormat)
-		if err != nil {
+	// Convertir le fichier au fil de l'eau; le résultat ne remplace le
+	// fichier de sortie qu'une fois la conversion réussie
+	err = converter.WriteFile(outputFile, source, func(w io.Writer) error {
+		if err := pipeline.ConvertStream(ctx, reader, w, opts); err != nil {
 			return fmt.Errorf("erreur lors de la conversion: %v", err)
 		}
+		return nil
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
 
-		// Générer le nom du fichier de sortie
-		baseName := filepath.Base(inputFile)
-		baseNameWithoutExt := baseName[:len(baseName)-len(filepath.Ext(baseName))]
-		outputFile := filepath.Join(outputDir, fmt.Sprintf("%s.%s", baseNameWithoutExt, outputFormat))
+	result.Output = outputFile
+	return result
+}
 
-		// Sauvegarder le résultat
-		if err := os.WriteFile(outputFile, result, 0644); err != nil {
-			return fmt.Errorf("erreur lors de la sauvegarde du fichier: %v", err)
-		}
+// planConversion détecte les couches du contenu de r, nommé d'après in (sauf
+// si elles sont imposées par --from), et calcule les conversions vers
+// outputFormat. Le lecteur retourné remplace r, dont le début a pu être lu.
+func planConversion(r io.Reader, in inputFileRef) ([]string, io.Reader, *converter.Pipeline, error) {
+	var (
+		layers []string
+		reader = r
+		err    error
+	)
+	if inputFrom != "" {
+		layers, err = converter.ParseLayers(inputFrom)
+	} else {
+		layers, reader, err = converter.SniffLayers(r, in.Path)
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("erreur lors de la lecture du fichier: %v", err)
+	}
 
-		fmt.Printf("Conversion réussie ! Fichier sauvegardé : %s\n", outputFile)
-		return nil
-	},
+	// Passer par chaque étape demandée ("csv+gzip")
+	pipeline, err := converter.PlanRoute(layers, outputFormat)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return layers, reader, pipeline, nil
 }
 
+// outputPath retourne le fichier produit pour in sous outputDir, en
+// reproduisant son chemin relatif
+func outputPath(in inputFileRef, outputDir string, pipeline *converter.Pipeline) string {
+	return filepath.Join(outputDir, filepath.Dir(in.Rel), pipeline.OutputFileName(in.Path))
+}
+
+// stdioName désigne l'entrée ou la sortie standard dans -i et -o
+const stdioName = "-"
+
+// stdinRef représente l'entrée standard; le fichier produit se nomme "stdin.<format>"
+var stdinRef = inputFileRef{Path: "stdin", Rel: "stdin", Stdin: true}
+
+var showGraph bool
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Liste les formats supportés",
 	Run: func(cmd *cobra.Command, args []string) {
+		if showGraph {
+			printGraph()
+			return
+		}
+
 		fmt.Println("Formats supportés :")
-		fmt.Println("Texte :")
-		fmt.Println("  - json")
-		fmt.Println("  - csv")
-		fmt.Println("  - xml")
-		fmt.Println("  - txt")
-		fmt.Println("\nImage :")
-		fmt.Println("  - jpeg")
-		fmt.Println("  - png")
-		fmt.Println("  - gif")
-		fmt.Println("\nCompression :")
-		fmt.Println("  - gzip")
-		fmt.Println("  - gunzip")
+
+		// Regrouper les formats par catégorie
+		byCategory := make(map[string][]converter.Format)
+		for _, f := range converter.DefaultRegistry.Formats() {
+			byCategory[f.Category] = append(byCategory[f.Category], f)
+		}
+
+		for i, category := range converter.Categories {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s :\n", category)
+			for _, f := range byCategory[category] {
+				line := "  - " + f.Name
+				if len(f.Aliases) > 0 {
+					line += fmt.Sprintf(" (alias : %s)", strings.Join(f.Aliases, ", "))
+				}
+				if f.Unwraps != "" {
+					line += fmt.Sprintf(" [décompresse %s]", f.Unwraps)
+				}
+				if !converter.DefaultRegistry.Convertible(f.Name) {
+					line += " [détection uniquement]"
+				}
+				fmt.Println(line)
+			}
+		}
 	},
 }
 
+// printGraph affiche les conversions directes, regroupées par format d'entrée
+func printGraph() {
+	type group struct {
+		from, conv string
+		to         []string
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, edge := range converter.DefaultRegistry.Graph() {
+		key := edge.From + "/" + edge.Converter
+		g, ok := index[key]
+		if !ok {
+			g = &group{from: edge.From, conv: edge.Converter}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.to = append(g.to, edge.To)
+	}
+
+	fmt.Println("Graphe de conversion :")
+	for _, g := range groups {
+		fmt.Printf("  %-6s → %s (%s)\n", g.from, strings.Join(g.to, ", "), g.conv)
+	}
+	fmt.Println("\nLes chemins à plusieurs étapes sont calculés automatiquement (ex: .csv.gz → gunzip → xml).")
+}
+
 func init() {
 	// Ajouter les commandes au rootCmd
 	rootCmd.AddCommand(convertCmd)
 	rootCmd.AddCommand(listCmd)
 
 	// Ajouter les flags à la commande convert
-	convertCmd.Flags().StringVarP(&inputFile, "input", "i", "", "Fichier d'entrée à convertir")
-	convertCmd.Flags().StringVarP(&outputFormat, "format", "f", "", "Format de sortie")
-	convertCmd.Flags().StringVarP(&outputDir, "output", "o", "result", "Dossier de sortie")
+	convertCmd.Flags().StringVarP(&inputFile, "input", "i", "", "Fichier, dossier ou motif d'entrée à convertir (\"-\" pour l'entrée standard)")
+	convertCmd.Flags().StringVarP(&outputFormat, "format", "f", "", "Format de sortie (ex: csv, ou csv+gzip pour enchaîner)")
+	convertCmd.Flags().StringVarP(&outputDir, "output", "o", "result", "Dossier de sortie (\"-\" pour la sortie standard)")
+	convertCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Parcourt les sous-dossiers de l'entrée")
+	convertCmd.Flags().IntVarP(&jobs, "jobs", "j", runtime.GOMAXPROCS(0), "Nombre de conversions simultanées")
+	convertCmd.Flags().StringVar(&inputFrom, "from", "", "Format d'entrée (ex: csv, ou csv.gz), détecté d'après le contenu par défaut")
+	convertCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Arrête le traitement par lot au premier échec")
+
+	addFormatFlags(convertCmd)
+
+	listCmd.Flags().BoolVarP(&showGraph, "graph", "g", false, "Affiche le graphe des conversions")
 
 	// Marquer les flags requis
 	convertCmd.MarkFlagRequired("input")
@@ -115,7 +310,7 @@ func init() {
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertFileNamedStdin(t *testing.T) {
+	defer func(format string) { outputFormat = format }(outputFormat)
+	outputFormat = "json"
+
+	// "-i stdin" désigne un fichier du dossier courant
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.WriteFile("stdin", []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	refs, _, err := collectInputs("stdin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs[0].Stdin {
+		t.Fatal("fichier nommé stdin pris pour l'entrée standard")
+	}
+
+	result := convertFile(context.Background(), refs[0], "out")
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if _, err := os.Stat(filepath.Join("out", "stdin.json")); err != nil {
+		t.Errorf("fichier de sortie absent: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"file-converter/internal/converter"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Options de lecture et d'écriture propres à chaque format
+var (
+	csvDelimiter string
+	csvQuote     string
+	csvNoHeader  bool
+	csvHeader    bool
+	outDelimiter string
+	outQuote     string
+	csvCRLF      bool
+	encoding     string
+	outEncoding  string
+	jsonIndent   int
+	xmlRoot      string
+	xmlItem      string
+	xmlPath      string
+	xmlStyle     string
+	xmlNS        []string
+	xmlIndent    int
+	txtLayout    string
+	txtWidths    string
+	htmlTable    int
+	columns      []string
+	flattenMode  string
+	flattenSep   string
+	inferTypes   bool
+	schema       string
+
+	// formatOptions est construit à partir des flags avant la conversion
+	formatOptions converter.ConvertOptions
+)
+
+// addFormatFlags ajoute les options de format à une commande
+func addFormatFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&csvDelimiter, "delimiter", "", "Séparateur CSV (ex: \";\", \"tab\"), détecté à la lecture par défaut")
+	cmd.Flags().StringVar(&csvQuote, "quote", "", "Caractère de citation CSV, détecté à la lecture par défaut")
+	cmd.Flags().BoolVar(&csvNoHeader, "no-header", false, "CSV sans ligne d'en-tête (colonnes col1, col2...)")
+	cmd.Flags().BoolVar(&csvHeader, "header", false, "Lit toujours la première ligne CSV comme en-tête, sans détection")
+	cmd.Flags().StringVar(&outDelimiter, "output-delimiter", "", "Séparateur CSV en sortie, s'il diffère de celui de l'entrée")
+	cmd.Flags().StringVar(&outQuote, "output-quote", "", "Caractère de citation CSV en sortie")
+	cmd.Flags().BoolVar(&csvCRLF, "crlf", false, "Termine les lignes CSV par \\r\\n")
+	cmd.Flags().StringVar(&encoding, "encoding", "", "Encodage du texte d'entrée (ex: utf-16, latin1, windows-1252), détecté par défaut")
+	cmd.Flags().StringVar(&outEncoding, "output-encoding", "", "Encodage du texte de sortie, UTF-8 par défaut")
+	cmd.Flags().IntVar(&jsonIndent, "json-indent", 2, "Indentation JSON en espaces, 0 pour une sortie compacte")
+	cmd.Flags().StringVar(&xmlRoot, "xml-root", "", "Nom de l'élément racine XML (root en sortie par défaut, vérifié en entrée s'il est donné)")
+	cmd.Flags().StringVar(&xmlItem, "xml-item", "", "Nom de l'élément XML d'un enregistrement (item en sortie par défaut, tout enfant de la racine en entrée)")
+	cmd.Flags().StringVar(&xmlStyle, "xml-style", converter.XMLFields, "Forme des champs XML écrits: field (<field name=...>), element (<email>) ou attribute")
+	cmd.Flags().StringArrayVar(&xmlNS, "xml-ns", nil, "Espace de noms déclaré sur la racine XML, prefixe=uri ou =uri (répétable)")
+	cmd.Flags().IntVar(&xmlIndent, "xml-indent", 2, "Indentation XML en espaces, 0 pour une sortie sans indentation")
+	cmd.Flags().StringVar(&xmlPath, "xml-path", "", "Chemin des éléments XML lus comme enregistrements (ex: catalog/book, //book)")
+	cmd.Flags().StringVar(&txtLayout, "txt-layout", converter.TXTBlocks, "Mise en forme TXT écrite: block (clé: valeur) ou table (colonnes alignées)")
+	cmd.Flags().StringVar(&txtWidths, "txt-widths", "", "Colonnes TXT à largeur fixe, lues et écrites sans en-tête (ex: id:4,nom:20)")
+	cmd.Flags().IntVar(&htmlTable, "html-table", 0, "Tableau HTML lu (1 pour le premier de la page), tous par défaut")
+	cmd.Flags().StringVar(&flattenMode, "flatten", converter.FlattenPath, "Aplatissement des valeurs imbriquées en CSV/TXT: path (address.city, tags[0]), json, ou none pour lire les colonnes telles quelles")
+	cmd.Flags().StringVar(&flattenSep, "flatten-separator", ".", "Séparateur des niveaux d'un chemin aplati")
+	cmd.Flags().BoolVar(&inferTypes, "infer", false, "Déduit le type des valeurs CSV, XML, TXT et INI (nombres, booléens, null, dates)")
+	cmd.Flags().StringVar(&schema, "schema", "", "Type imposé par colonne (ex: age=integer,code=string)")
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "Colonnes à garder, dans l'ordre voulu (ex: nom,email)")
+}
+
+// buildFormatOptions valide les flags de format et remplit formatOptions
+func buildFormatOptions() error {
+	delimiter, err := parseCharFlag(csvDelimiter, "séparateur")
+	if err != nil {
+		return err
+	}
+	quote, err := parseCharFlag(csvQuote, "caractère de citation")
+	if err != nil {
+		return err
+	}
+	output := converter.CSVOptions{CRLF: csvCRLF}
+	if output.Delimiter, err = parseCharFlag(outDelimiter, "séparateur de sortie"); err != nil {
+		return err
+	}
+	if output.Quote, err = parseCharFlag(outQuote, "caractère de citation de sortie"); err != nil {
+		return err
+	}
+	types, err := converter.ParseSchema(schema)
+	if err != nil {
+		return err
+	}
+	if jsonIndent < 0 {
+		return fmt.Errorf("indentation JSON invalide: %d", jsonIndent)
+	}
+	if xmlIndent < 0 {
+		return fmt.Errorf("indentation XML invalide: %d", xmlIndent)
+	}
+	widths, err := converter.ParseWidths(txtWidths)
+	if err != nil {
+		return err
+	}
+	var namespaces map[string]string
+	for _, spec := range xmlNS {
+		prefix, uri, err := converter.ParseNamespace(spec)
+		if err != nil {
+			return err
+		}
+		if namespaces == nil {
+			namespaces = make(map[string]string)
+		}
+		namespaces[prefix] = uri
+	}
+
+	xmlOptions := converter.XMLOptions{
+		Root:       xmlRoot,
+		Item:       xmlItem,
+		Path:       xmlPath,
+		Style:      xmlStyle,
+		Namespaces: namespaces,
+		Indent:     strings.Repeat(" ", xmlIndent),
+		Compact:    xmlIndent == 0,
+	}
+	opts := converter.ConvertOptions{
+		Columns:   columns,
+		CSV:       converter.CSVOptions{Delimiter: delimiter, Quote: quote, NoHeader: csvNoHeader, Header: csvHeader},
+		CSVOutput: output,
+		JSON:      converter.JSONOptions{Indent: strings.Repeat(" ", jsonIndent), Compact: jsonIndent == 0},
+		XML:       xmlOptions,
+		TXT:       converter.TXTOptions{Layout: txtLayout, Widths: widths},
+		HTML:      converter.HTMLOptions{Table: htmlTable},
+		Flatten:   converter.FlattenOptions{Mode: flattenMode, Separator: flattenSep},
+		Types:     converter.TypeOptions{Infer: inferTypes, Schema: types},
+
+		Encoding:       encoding,
+		OutputEncoding: outEncoding,
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	formatOptions = opts
+	return nil
+}
+
+// parseCharFlag lit un caractère d'option; vide, il est détecté ou pris par défaut
+func parseCharFlag(value, label string) (rune, error) {
+	if value == "" {
+		return 0, nil
+	}
+	r, err := converter.ParseChar(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s invalide: %v", label, err)
+	}
+	return r, nil
+}
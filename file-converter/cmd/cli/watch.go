@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"file-converter/internal/pool"
+	"file-converter/internal/watcher"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchIn       string
+	watchOut      string
+	watchArchive  string
+	watchErrors   string
+	watchSettle   time.Duration
+	watchInterval time.Duration
+	watchPolling  bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Convertit les fichiers déposés dans un dossier",
+	Long: `Surveille un dossier et convertit chaque fichier créé ou modifié, une fois
+son écriture terminée. Les originaux traités peuvent être déplacés dans un
+dossier d'archive, ou d'erreurs en cas d'échec.
+Exemple: converter watch --in inbox/ -f csv --out outbox/ --archive done/ --errors failed/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := buildFormatOptions(); err != nil {
+			return err
+		}
+		for _, dir := range []string{watchOut, watchArchive, watchErrors} {
+			if dir == "" {
+				continue
+			}
+			if sameDir(dir, watchIn) {
+				return fmt.Errorf("le dossier %s ne peut pas être le dossier surveillé", dir)
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("impossible de créer le dossier %s: %v", dir, err)
+			}
+		}
+
+		var (
+			w   *watcher.Watcher
+			err error
+		)
+		if watchPolling {
+			w, err = watcher.NewPolling(watchIn, watchInterval)
+		} else {
+			w, err = watcher.New(watchIn, watchInterval)
+		}
+		if err != nil {
+			return fmt.Errorf("impossible de surveiller %s: %v", watchIn, err)
+		}
+		defer w.Close()
+		cmd.SilenceUsage = true
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("Surveillance de %s (%s), conversion vers %s dans %s. Ctrl+C pour arrêter.\n",
+			watchIn, w.Mode, outputFormat, watchOut)
+
+		go func() {
+			for err := range w.Errors {
+				fmt.Fprintf(os.Stderr, "erreur de surveillance: %v\n", err)
+			}
+		}()
+
+		// Filtrer les fichiers temporaires avant la stabilisation
+		events := make(chan string)
+		go func() {
+			defer close(events)
+			for path := range w.Events {
+				if watcher.Ignored(path) {
+					continue
+				}
+				select {
+				case events <- path:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		workers := pool.New(jobs)
+		var (
+			wg      sync.WaitGroup
+			pending inFlight
+		)
+		watcher.Settle(ctx, events, watchSettle, func(path string) {
+			if !pending.start(path) {
+				return
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					workers.Do(ctx, func() error {
+						processWatched(ctx, path)
+						return nil
+					})
+					if !pending.done(path) || ctx.Err() != nil {
+						return
+					}
+				}
+			}()
+		})
+
+		wg.Wait()
+		fmt.Println("Surveillance arrêtée.")
+		return nil
+	},
+}
+
+// inFlight évite de convertir le même fichier deux fois en parallèle. Un
+// événement reçu pendant la conversion n'est pas perdu: la conversion est
+// relancée ensuite, pour traiter la dernière version du fichier.
+type inFlight struct {
+	mu      sync.Mutex
+	running map[string]bool
+	again   map[string]bool
+}
+
+// start retourne true si path doit être converti maintenant; s'il l'est
+// déjà, sa conversion sera relancée
+func (f *inFlight) start(path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running == nil {
+		f.running = make(map[string]bool)
+		f.again = make(map[string]bool)
+	}
+	if f.running[path] {
+		f.again[path] = true
+		return false
+	}
+	f.running[path] = true
+	return true
+}
+
+// done termine une conversion de path et retourne true si elle doit être relancée
+func (f *inFlight) done(path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.again[path] {
+		delete(f.again, path)
+		return true
+	}
+	delete(f.running, path)
+	return false
+}
+
+// processWatched convertit un fichier stabilisé puis range l'original
+func processWatched(ctx context.Context, path string) {
+	before, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		// Déjà rangé par une conversion précédente, ou supprimé
+		return
+	}
+
+	in := inputFileRef{Path: path, Rel: filepath.Base(path)}
+	result := convertFile(ctx, in, watchOut)
+	stamp := time.Now().Format("15:04:05")
+
+	target := watchArchive
+	if result.Err != nil && ctx.Err() != nil {
+		// Conversion interrompue: l'original reste dans le dossier surveillé
+		fmt.Printf("[%s] - %s : annulé\n", stamp, path)
+		return
+	}
+	if result.Err != nil {
+		fmt.Printf("[%s] ✗ %s : %v\n", stamp, path, result.Err)
+		target = watchErrors
+	} else {
+		fmt.Printf("[%s] ✓ %s → %s\n", stamp, path, result.Output)
+	}
+
+	if target == "" {
+		return
+	}
+	// Un fichier réécrit pendant la conversion reste en place: sa nouvelle
+	// version sera convertie à son tour
+	if after, err := os.Stat(path); err != nil || before == nil ||
+		!after.ModTime().Equal(before.ModTime()) || after.Size() != before.Size() {
+		return
+	}
+	dest := freePath(target, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		fmt.Fprintf(os.Stderr, "impossible de déplacer %s vers %s: %v\n", path, target, err)
+	}
+}
+
+// freePath retourne un chemin inutilisé pour name dans dir: name lui-même,
+// ou suivi d'un compteur (rapport-1.csv, rapport-2.csv...) pour ne pas
+// écraser un fichier déjà rangé
+func freePath(dir, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	path := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+}
+
+// sameDir indique si deux chemins désignent le même dossier
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVar(&watchIn, "in", "", "Dossier à surveiller")
+	watchCmd.Flags().StringVarP(&outputFormat, "format", "f", "", "Format de sortie (ex: csv, ou csv+gzip pour enchaîner)")
+	watchCmd.Flags().StringVar(&watchOut, "out", "result", "Dossier de sortie")
+	watchCmd.Flags().StringVar(&watchArchive, "archive", "", "Dossier où déplacer les originaux convertis")
+	watchCmd.Flags().StringVar(&watchErrors, "errors", "", "Dossier où déplacer les originaux en échec")
+	watchCmd.Flags().DurationVar(&watchSettle, "settle", 2*time.Second, "Délai sans écriture avant de convertir un fichier")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "Intervalle de scrutation lorsque inotify n'est pas disponible")
+	watchCmd.Flags().BoolVar(&watchPolling, "poll", false, "Force la scrutation périodique")
+	watchCmd.Flags().IntVarP(&jobs, "jobs", "j", runtime.GOMAXPROCS(0), "Nombre de conversions simultanées")
+
+	addFormatFlags(watchCmd)
+
+	watchCmd.MarkFlagRequired("in")
+	watchCmd.MarkFlagRequired("format")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInFlight(t *testing.T) {
+	var f inFlight
+	if !f.start("a.json") {
+		t.Fatal("première conversion refusée")
+	}
+	if f.start("a.json") {
+		t.Fatal("conversion lancée deux fois en parallèle")
+	}
+	if !f.start("b.json") {
+		t.Fatal("conversion d'un autre fichier refusée")
+	}
+	// Un événement reçu pendant la conversion la fait relancer une fois
+	if !f.done("a.json") {
+		t.Fatal("événement reçu pendant la conversion perdu")
+	}
+	if f.done("a.json") {
+		t.Fatal("conversion relancée sans nouvel événement")
+	}
+	if !f.start("a.json") {
+		t.Fatal("fichier toujours marqué en cours après la fin de sa conversion")
+	}
+}
+
+func TestFreePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{name: "nom libre", want: "a.json"},
+		{name: "nom pris", existing: []string{"a.json"}, want: "a-1.json"},
+		{name: "compteur pris", existing: []string{"a.json", "a-1.json"}, want: "a-2.json"},
+		{name: "sans extension", existing: []string{"notes"}, want: "notes-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			name := "a.json"
+			if len(tt.existing) > 0 {
+				name = tt.existing[0]
+			}
+			if got := freePath(dir, name); got != filepath.Join(dir, tt.want) {
+				t.Errorf("freePath() = %s, attendu %s", got, filepath.Join(dir, tt.want))
+			}
+		})
+	}
+}
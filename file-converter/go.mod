@@ -2,10 +2,16 @@ module file-converter
 
 go 1.21
 
-require github.com/gorilla/mux v1.8.1
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/gorilla/mux v1.8.1
+	github.com/spf13/cobra v1.9.1
+	golang.org/x/net v0.31.0
+	golang.org/x/text v0.20.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.9.1 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
 )
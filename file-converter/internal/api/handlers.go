@@ -1,13 +1,27 @@
 package api
 
 import (
+	"bytes"
 	"file-converter/internal/converter"
+	"file-converter/internal/pool"
 	"io"
+	"log"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
 
+// workers limite le nombre de conversions simultanées pour que les requêtes
+// concurrentes (encodage d'images notamment) ne surchargent pas le processeur
+var workers = pool.New(0)
+
+// SetWorkers remplace le pool de workers partagé par les handlers
+func SetWorkers(p *pool.Pool) {
+	workers = p
+}
+
 func RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/convert/{format}", convertHandler).Methods("POST")
 }
@@ -16,67 +30,129 @@ func convertHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	format := vars["format"]
 
+	// Lire les options de format
+	opts, err := parseOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Lire le fichier
-	file, _, err := r.FormFile("file")
+	file, err := formFile(r, "file")
 	if err != nil {
 		http.Error(w, "Erreur lors de la lecture du fichier", http.StatusBadRequest)
 		return
 	}
 	defer file.Close()
 
-	content, err := io.ReadAll(file)
-	if err != nil {
-		http.Error(w, "Erreur lors de la lecture du contenu", http.StatusInternalServerError)
-		return
+	// Détecter le format envoyé à partir du contenu (le nom du fichier sert d'indice),
+	// sauf s'il est imposé par le paramètre from ("csv", "csv.gz")
+	var (
+		layers  []string
+		content io.Reader = file
+	)
+	if from := r.URL.Query().Get("from"); from != "" {
+		layers, err = converter.ParseLayers(from)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		layers, content, err = converter.SniffLayers(file, file.FileName())
+		if err != nil {
+			http.Error(w, "Erreur lors de la lecture du contenu", http.StatusBadRequest)
+			return
+		}
 	}
 
-	// Sélectionner le convertisseur
-	var conv converter.Converter
-	switch format {
-	case "json", "csv", "xml", "txt":
-		conv = &converter.TextConverter{}
-	case "jpeg", "png", "gif":
-		conv = &converter.ImageConverter{}
-	case "gzip", "gunzip":
-		conv = &converter.CompressConverter{}
-	default:
-		http.Error(w, "Format non supporté", http.StatusBadRequest)
+	// Calculer les conversions à appliquer
+	pipeline, err := converter.PlanRoute(layers, format)
+	if err != nil {
+		http.Error(w, "Format non supporté: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	f := pipeline.Output()
+	w.Header().Set("X-Conversion-Path", pipeline.String())
 
-	// Convertir
-	result, err := conv.Convert(content, format)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// Attendre une place libre dans le pool de workers
+	if err := workers.Acquire(r.Context()); err != nil {
+		http.Error(w, "Requête annulée", http.StatusServiceUnavailable)
 		return
 	}
+	defer workers.Release()
 
 	// Définir le bon Content-Type en fonction du format
-	contentType := getContentType(format)
-	w.Header().Set("Content-Type", contentType)
-	w.Write(result)
+	w.Header().Set("Content-Type", f.ContentType())
+
+	// Convertir au fil de l'eau vers la réponse
+	out := &responseBuffer{w: w}
+	opts.OutputFormat = f.Name
+	if err := pipeline.ConvertStream(r.Context(), content, out, opts); err != nil {
+		if !out.flushed {
+			w.Header().Del("Content-Type")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Le statut est déjà envoyé: couper la connexion pour que le client
+		// ne prenne pas la réponse tronquée pour un succès
+		log.Printf("conversion interrompue: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	if !out.flushed {
+		w.Header().Set("Content-Length", strconv.Itoa(out.buf.Len()))
+	}
+	if err := out.Flush(); err != nil {
+		log.Printf("erreur lors de l'envoi de la réponse: %v", err)
+	}
+}
+
+// maxBufferedResponse est la taille de début de réponse gardée en mémoire
+var maxBufferedResponse = 1 << 20
+
+// responseBuffer garde en mémoire le début de la réponse: une erreur de
+// conversion survenue avant maxBufferedResponse octets donne encore un
+// statut d'erreur. Au-delà, la réponse est envoyée au fil de l'eau.
+type responseBuffer struct {
+	w       io.Writer
+	buf     bytes.Buffer
+	flushed bool
 }
 
-// getContentType retourne le Content-Type approprié pour chaque format
-func getContentType(format string) string {
-	switch format {
-	case "json":
-		return "application/json"
-	case "csv":
-		return "text/csv"
-	case "xml":
-		return "application/xml"
-	case "txt":
-		return "text/plain"
-	case "jpeg":
-		return "image/jpeg"
-	case "png":
-		return "image/png"
-	case "gif":
-		return "image/gif"
-	case "gzip":
-		return "application/gzip"
-	default:
-		return "application/octet-stream"
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	if b.flushed {
+		return b.w.Write(p)
 	}
-}
\ No newline at end of file
+	b.buf.Write(p)
+	if b.buf.Len() >= maxBufferedResponse {
+		return len(p), b.Flush()
+	}
+	return len(p), nil
+}
+
+// Flush envoie le contenu gardé en mémoire; les écritures suivantes ne sont
+// plus retenues
+func (b *responseBuffer) Flush() error {
+	b.flushed = true
+	_, err := b.w.Write(b.buf.Bytes())
+	b.buf.Reset()
+	return err
+}
+
+// formFile retourne la partie multipart demandée sans charger le formulaire
+// en mémoire ni sur disque: le contenu est lu au fil de la conversion
+func formFile(r *http.Request, name string) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
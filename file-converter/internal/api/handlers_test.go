@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestConvertHandlerErrors(t *testing.T) {
+	// Dernière ligne invalide: l'erreur survient après les premiers enregistrements
+	valid := "id,nom\n" + strings.Repeat("1,Zoé\n", 1000)
+	invalid := valid + "2,\"Zoé\n"
+
+	tests := []struct {
+		name       string
+		input      string
+		buffer     int // Taille du début de réponse gardée en mémoire
+		wantStatus int
+		wantAbort  bool // La connexion est coupée pendant la réponse
+	}{
+		{name: "succès", input: valid, buffer: 1 << 20, wantStatus: http.StatusOK},
+		{name: "erreur avant l'envoi", input: invalid, buffer: 1 << 20, wantStatus: http.StatusInternalServerError},
+		{name: "erreur après l'envoi", input: invalid, buffer: 64, wantStatus: http.StatusOK, wantAbort: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(size int) { maxBufferedResponse = size }(maxBufferedResponse)
+			maxBufferedResponse = tt.buffer
+
+			router := mux.NewRouter()
+			RegisterRoutes(router)
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile("file", "data.csv")
+			io.WriteString(part, tt.input)
+			form.Close()
+
+			resp, err := http.Post(server.URL+"/convert/json", form.FormDataContentType(), &body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("statut = %d, attendu %d", resp.StatusCode, tt.wantStatus)
+			}
+			_, err = io.ReadAll(resp.Body)
+			if aborted := err != nil; aborted != tt.wantAbort {
+				t.Errorf("lecture de la réponse: erreur = %v, coupure attendue %v", err, tt.wantAbort)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"file-converter/internal/converter"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// parseOptions lit les options de format passées en paramètres de requête:
+// columns, delimiter, quote, header, output_delimiter, output_quote, crlf,
+// json_indent, xml_root, xml_item, xml_path, xml_style, xml_ns, xml_indent, txt_layout, txt_widths,
+// html_table, flatten, flatten_separator, infer, schema, encoding et output_encoding
+func parseOptions(r *http.Request) (converter.ConvertOptions, error) {
+	var opts converter.ConvertOptions
+	query := r.URL.Query()
+
+	if value := query.Get("columns"); value != "" {
+		opts.Columns = strings.Split(value, ",")
+	}
+	if value := query.Get("delimiter"); value != "" {
+		delimiter, err := converter.ParseChar(value)
+		if err != nil {
+			return opts, fmt.Errorf("delimiter invalide: %v", err)
+		}
+		opts.CSV.Delimiter = delimiter
+	}
+	if value := query.Get("quote"); value != "" {
+		quote, err := converter.ParseChar(value)
+		if err != nil {
+			return opts, fmt.Errorf("quote invalide: %v", err)
+		}
+		opts.CSV.Quote = quote
+	}
+	if value := query.Get("header"); value != "" {
+		header, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("header invalide: %s", value)
+		}
+		opts.CSV.Header = header
+		opts.CSV.NoHeader = !header
+	}
+	if value := query.Get("output_delimiter"); value != "" {
+		delimiter, err := converter.ParseChar(value)
+		if err != nil {
+			return opts, fmt.Errorf("output_delimiter invalide: %v", err)
+		}
+		opts.CSVOutput.Delimiter = delimiter
+	}
+	if value := query.Get("output_quote"); value != "" {
+		quote, err := converter.ParseChar(value)
+		if err != nil {
+			return opts, fmt.Errorf("output_quote invalide: %v", err)
+		}
+		opts.CSVOutput.Quote = quote
+	}
+	if value := query.Get("crlf"); value != "" {
+		crlf, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("crlf invalide: %s", value)
+		}
+		opts.CSVOutput.CRLF = crlf
+	}
+	if value := query.Get("json_indent"); value != "" {
+		indent, err := strconv.Atoi(value)
+		if err != nil || indent < 0 {
+			return opts, fmt.Errorf("json_indent invalide: %s", value)
+		}
+		opts.JSON.Indent = strings.Repeat(" ", indent)
+		opts.JSON.Compact = indent == 0
+	}
+	opts.XML.Root = query.Get("xml_root")
+	opts.XML.Item = query.Get("xml_item")
+	opts.XML.Path = query.Get("xml_path")
+	opts.XML.Style = query.Get("xml_style")
+	for _, spec := range query["xml_ns"] {
+		prefix, uri, err := converter.ParseNamespace(spec)
+		if err != nil {
+			return opts, err
+		}
+		if opts.XML.Namespaces == nil {
+			opts.XML.Namespaces = make(map[string]string)
+		}
+		opts.XML.Namespaces[prefix] = uri
+	}
+	if value := query.Get("xml_indent"); value != "" {
+		indent, err := strconv.Atoi(value)
+		if err != nil || indent < 0 {
+			return opts, fmt.Errorf("xml_indent invalide: %s", value)
+		}
+		opts.XML.Indent = strings.Repeat(" ", indent)
+		opts.XML.Compact = indent == 0
+	}
+	opts.TXT.Layout = query.Get("txt_layout")
+	if value := query.Get("txt_widths"); value != "" {
+		widths, err := converter.ParseWidths(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.TXT.Widths = widths
+	}
+	if value := query.Get("html_table"); value != "" {
+		table, err := strconv.Atoi(value)
+		if err != nil || table < 0 {
+			return opts, fmt.Errorf("html_table invalide: %s", value)
+		}
+		opts.HTML.Table = table
+	}
+	opts.Flatten.Mode = query.Get("flatten")
+	opts.Flatten.Separator = query.Get("flatten_separator")
+	if value := query.Get("infer"); value != "" {
+		infer, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("infer invalide: %s", value)
+		}
+		opts.Types.Infer = infer
+	}
+	if value := query.Get("schema"); value != "" {
+		schema, err := converter.ParseSchema(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.Types.Schema = schema
+	}
+
+	opts.Encoding = query.Get("encoding")
+	opts.OutputEncoding = query.Get("output_encoding")
+
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
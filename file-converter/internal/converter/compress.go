@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"context"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -32,145 +33,193 @@ var CompressFormats = []SupportedFormat{
 	{"ZIP", "zip", "application/zip"},
 }
 
+func init() {
+	RegisterFormat(Format{Name: "gzip", Label: "GZIP", Category: CategoryCompression, Aliases: []string{"gz"},
+		MIMETypes: []string{"application/gzip", "application/x-gzip"}, Extensions: []string{"gz", "gzip"}})
+	RegisterFormat(Format{Name: "zlib", Label: "ZLIB", Category: CategoryCompression,
+		MIMETypes: []string{"application/zlib"}, Extensions: []string{"zlib"}})
+	RegisterFormat(Format{Name: "zip", Label: "ZIP", Category: CategoryCompression,
+		MIMETypes: []string{"application/zip"}, Extensions: []string{"zip"}})
+
+	// Les décompressions sont des opérations: elles retirent le conteneur
+	// et rendent le contenu d'origine, sans extension propre
+	RegisterFormat(Format{Name: "gunzip", Label: "GUNZIP", Category: CategoryCompression, Unwraps: "gzip"})
+	RegisterFormat(Format{Name: "unzlib", Label: "UNZLIB", Category: CategoryCompression, Unwraps: "zlib"})
+	RegisterFormat(Format{Name: "unzip", Label: "UNZIP", Category: CategoryCompression, Unwraps: "zip"})
+
+	newCompress := func() Converter { return NewCompressConverter() }
+	RegisterConverter(Registration{
+		Name:    "compress",
+		Inputs:  []string{"*"},
+		Outputs: []string{"gzip", "zlib", "zip"},
+		New:     newCompress,
+	})
+	for _, op := range []string{"gunzip", "unzlib", "unzip"} {
+		f, _ := LookupFormat(op)
+		RegisterConverter(Registration{
+			Name:    "decompress",
+			Inputs:  []string{f.Unwraps},
+			Outputs: []string{op},
+			New:     newCompress,
+		})
+	}
+}
+
 // Convert implémente l'interface Converter pour la compression
 func (c *CompressConverter) Convert(input []byte, outputFormat string) ([]byte, error) {
-	switch outputFormat {
+	return convertBytes(c, input, outputFormat)
+}
+
+// ConvertStream compresse ou décompresse r vers w au fil de l'eau
+func (c *CompressConverter) ConvertStream(ctx context.Context, r io.Reader, w io.Writer, opts ConvertOptions) error {
+	// ZIP a besoin d'un accès aléatoire: on conserve le lecteur d'origine s'il le permet
+	if opts.OutputFormat == "unzip" {
+		return c.decompressZip(ctx, r, w)
+	}
+
+	r = newContextReader(ctx, r)
+	switch opts.OutputFormat {
 	case "gz", "gzip":
-		return c.compressGzip(input)
+		return c.compressGzip(r, w)
 	case "gunzip":
-		return c.decompressGzip(input)
+		return c.decompressGzip(r, w)
 	case "zlib":
-		return c.compressZlib(input)
+		return c.compressZlib(r, w)
 	case "unzlib":
-		return c.decompressZlib(input)
+		return c.decompressZlib(r, w)
 	case "zip":
-		return c.compressZip(input)
-	case "unzip":
-		return c.decompressZip(input)
+		return c.compressZip(r, w)
 	default:
-		return nil, fmt.Errorf("format de compression non supporté: %s", outputFormat)
+		return fmt.Errorf("format de compression non supporté: %s", opts.OutputFormat)
 	}
 }
 
 // Compression GZIP
-func (c *CompressConverter) compressGzip(input []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	gw, err := gzip.NewWriterLevel(&buf, c.CompressionLevel)
+func (c *CompressConverter) compressGzip(r io.Reader, w io.Writer) error {
+	gw, err := gzip.NewWriterLevel(w, c.CompressionLevel)
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'initialisation gzip: %v", err)
+		return fmt.Errorf("erreur d'initialisation gzip: %v", err)
 	}
 
-	if _, err := gw.Write(input); err != nil {
-		return nil, fmt.Errorf("erreur de compression gzip: %v", err)
+	if _, err := io.Copy(gw, r); err != nil {
+		return fmt.Errorf("erreur de compression gzip: %v", err)
 	}
 
 	if err := gw.Close(); err != nil {
-		return nil, fmt.Errorf("erreur de fermeture gzip: %v", err)
+		return fmt.Errorf("erreur de fermeture gzip: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // Décompression GZIP
-func (c *CompressConverter) decompressGzip(input []byte) ([]byte, error) {
-	gr, err := gzip.NewReader(bytes.NewReader(input))
+func (c *CompressConverter) decompressGzip(r io.Reader, w io.Writer) error {
+	gr, err := gzip.NewReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'ouverture gzip: %v", err)
+		return fmt.Errorf("erreur d'ouverture gzip: %v", err)
 	}
 	defer gr.Close()
 
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, gr); err != nil {
-		return nil, fmt.Errorf("erreur de décompression gzip: %v", err)
+	if _, err := io.Copy(w, gr); err != nil {
+		return fmt.Errorf("erreur de décompression gzip: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // Compression ZLIB
-func (c *CompressConverter) compressZlib(input []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	zw, err := zlib.NewWriterLevel(&buf, c.CompressionLevel)
+func (c *CompressConverter) compressZlib(r io.Reader, w io.Writer) error {
+	zw, err := zlib.NewWriterLevel(w, c.CompressionLevel)
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'initialisation zlib: %v", err)
+		return fmt.Errorf("erreur d'initialisation zlib: %v", err)
 	}
 
-	if _, err := zw.Write(input); err != nil {
-		return nil, fmt.Errorf("erreur de compression zlib: %v", err)
+	if _, err := io.Copy(zw, r); err != nil {
+		return fmt.Errorf("erreur de compression zlib: %v", err)
 	}
 
 	if err := zw.Close(); err != nil {
-		return nil, fmt.Errorf("erreur de fermeture zlib: %v", err)
+		return fmt.Errorf("erreur de fermeture zlib: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // Décompression ZLIB
-func (c *CompressConverter) decompressZlib(input []byte) ([]byte, error) {
-	zr, err := zlib.NewReader(bytes.NewReader(input))
+func (c *CompressConverter) decompressZlib(r io.Reader, w io.Writer) error {
+	zr, err := zlib.NewReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'ouverture zlib: %v", err)
+		return fmt.Errorf("erreur d'ouverture zlib: %v", err)
 	}
 	defer zr.Close()
 
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, zr); err != nil {
-		return nil, fmt.Errorf("erreur de décompression zlib: %v", err)
+	if _, err := io.Copy(w, zr); err != nil {
+		return fmt.Errorf("erreur de décompression zlib: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // Compression ZIP
-func (c *CompressConverter) compressZip(input []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	zw := zip.NewWriter(&buf)
+func (c *CompressConverter) compressZip(r io.Reader, w io.Writer) error {
+	zw := zip.NewWriter(w)
 
 	// Créer un fichier dans l'archive
 	f, err := zw.Create("file")
 	if err != nil {
-		return nil, fmt.Errorf("erreur de création du fichier zip: %v", err)
+		return fmt.Errorf("erreur de création du fichier zip: %v", err)
 	}
 
 	// Écrire les données
-	if _, err := f.Write(input); err != nil {
-		return nil, fmt.Errorf("erreur d'écriture zip: %v", err)
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("erreur d'écriture zip: %v", err)
 	}
 
 	if err := zw.Close(); err != nil {
-		return nil, fmt.Errorf("erreur de fermeture zip: %v", err)
+		return fmt.Errorf("erreur de fermeture zip: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // Décompression ZIP
-func (c *CompressConverter) decompressZip(input []byte) ([]byte, error) {
-	reader := bytes.NewReader(input)
-	zr, err := zip.NewReader(reader, int64(len(input)))
+// Le répertoire central se trouve en fin d'archive: si r ne permet pas
+// l'accès aléatoire, l'archive est d'abord chargée en mémoire.
+func (c *CompressConverter) decompressZip(ctx context.Context, r io.Reader, w io.Writer) error {
+	var readerAt io.ReaderAt
+	var size int64
+	if br, ok := r.(*bytes.Reader); ok {
+		readerAt, size = br, br.Size()
+	} else {
+		input, err := io.ReadAll(newContextReader(ctx, r))
+		if err != nil {
+			return fmt.Errorf("erreur de lecture zip: %v", err)
+		}
+		readerAt, size = bytes.NewReader(input), int64(len(input))
+	}
+
+	zr, err := zip.NewReader(readerAt, size)
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'ouverture zip: %v", err)
+		return fmt.Errorf("erreur d'ouverture zip: %v", err)
 	}
 
 	// Lire le premier fichier de l'archive
 	if len(zr.File) == 0 {
-		return nil, fmt.Errorf("archive zip vide")
+		return fmt.Errorf("archive zip vide")
 	}
 
 	// Ouvrir le fichier
 	f, err := zr.File[0].Open()
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'ouverture du fichier dans zip: %v", err)
+		return fmt.Errorf("erreur d'ouverture du fichier dans zip: %v", err)
 	}
 	defer f.Close()
 
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, f); err != nil {
-		return nil, fmt.Errorf("erreur de lecture du fichier zip: %v", err)
+	if _, err := io.Copy(w, newContextReader(ctx, f)); err != nil {
+		return fmt.Errorf("erreur de lecture du fichier zip: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // SetCompressionLevel définit le niveau de compression
@@ -204,8 +253,13 @@ func (c *CompressConverter) IsCompressedFile(filename string) bool {
 
 // GetCompressionRatio calcule le ratio de compression
 func (c *CompressConverter) GetCompressionRatio(original, compressed []byte) float64 {
-	if len(original) == 0 {
+	return CompressionRatio(int64(len(original)), int64(len(compressed)))
+}
+
+// CompressionRatio calcule le ratio de compression à partir des tailles
+func CompressionRatio(originalSize, compressedSize int64) float64 {
+	if originalSize == 0 {
 		return 0
 	}
-	return float64(len(compressed)) / float64(len(original))
+	return float64(compressedSize) / float64(originalSize)
 }
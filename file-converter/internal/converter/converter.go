@@ -2,6 +2,8 @@
 package converter
 
 import (
+    "bytes"
+    "context"
     "fmt"
     "mime/multipart"
     "os"
@@ -16,17 +18,49 @@ type SupportedFormat struct {
     ContentType string
 }
 
+// ConvertOptions regroupe les paramètres d'une conversion
+type ConvertOptions struct {
+    OutputFormat string // Format de sortie canonique
+    InputFormat  string // Format d'entrée, détecté d'après le contenu si vide
+    Columns      []string // Colonnes à garder, dans l'ordre voulu; toutes si vide
+
+    // Options propres à chaque format
+    CSV  CSVOptions
+    JSON JSONOptions
+    XML  XMLOptions
+    TXT  TXTOptions
+    HTML HTMLOptions
+
+    // Dialecte d'écriture CSV/TSV/PSV: les champs vides reprennent ceux de CSV
+    CSVOutput CSVOptions
+
+    // Aplatissement des valeurs imbriquées pour CSV et TXT
+    Flatten FlattenOptions
+
+    // Typage des valeurs lues sous forme de texte
+    Types TypeOptions
+
+    // Encodage des entrées texte (détecté si vide) et des sorties texte (UTF-8 si vide)
+    Encoding       string
+    OutputEncoding string
+}
+
 // Interface principale pour la conversion
 type Converter interface {
     Convert(input []byte, outputFormat string) ([]byte, error)
+    // ConvertStream lit r et écrit le résultat dans w au fil de l'eau,
+    // sans charger tout le fichier en mémoire lorsque le format le permet
+    ConvertStream(ctx context.Context, r io.Reader, w io.Writer, opts ConvertOptions) error
     GetSupportedFormats() []SupportedFormat
 }
 
 // BaseConverter implémente les fonctionnalités communes
 type BaseConverter struct{}
 
-// ValidateFormat vérifie si un format est valide
+// ValidateFormat vérifie si un format est valide; les alias du registre
+// sont acceptés ("yml" pour "yaml")
 func ValidateFormat(format string, supportedFormats []SupportedFormat) error {
+    format = canonicalFormat(format)
     for _, f := range supportedFormats {
         if f.Extension == format {
             return nil
@@ -35,23 +69,38 @@ func ValidateFormat(format string, supportedFormats []SupportedFormat) error {
     return fmt.Errorf("format non supporté: %s", format)
 }
 
+// canonicalFormat retourne le nom canonique d'un format, ou le nom tel quel
+// s'il est inconnu du registre
+func canonicalFormat(name string) string {
+    if f, ok := LookupFormat(name); ok {
+        return f.Name
+    }
+    return name
+}
+
 // ConvertFile convertit un fichier
 func ConvertFile(conv Converter, inputPath string, outputFormat string) error {
-    input, err := os.ReadFile(inputPath)
+    in, err := os.Open(inputPath)
     if err != nil {
         return fmt.Errorf("erreur de lecture: %v", err)
     }
+    defer in.Close()
 
-    output, err := conv.Convert(input, outputFormat)
-    if err != nil {
-        return fmt.Errorf("erreur de conversion: %v", err)
-    }
-
+    outputFormat = canonicalFormat(outputFormat)
     outputPath := fmt.Sprintf("%s.%s", 
         inputPath[:len(inputPath)-len(filepath.Ext(inputPath))],
         outputFormat)
+    if f, ok := LookupFormat(outputFormat); ok {
+        outputPath = filepath.Join(filepath.Dir(inputPath), OutputFileName(inputPath, f))
+    }
 
-    return os.WriteFile(outputPath, output, 0644)
+    opts := ConvertOptions{OutputFormat: outputFormat}
+    return WriteFile(outputPath, inputPath, func(w io.Writer) error {
+        if err := conv.ConvertStream(context.Background(), in, w, opts); err != nil {
+            return fmt.Errorf("erreur de conversion: %v", err)
+        }
+        return nil
+    })
 }
 
 // ConvertMultipartFile convertit un fichier multipart
@@ -62,10 +111,39 @@ func ConvertMultipartFile(conv Converter, file *multipart.FileHeader, format str
     }
     defer src.Close()
 
-    content, err := io.ReadAll(src)
-    if err != nil {
-        return nil, fmt.Errorf("erreur de lecture: %v", err)
+    var buf bytes.Buffer
+    if err := conv.ConvertStream(context.Background(), src, &buf, ConvertOptions{OutputFormat: canonicalFormat(format)}); err != nil {
+        return nil, err
     }
+    return buf.Bytes(), nil
+}
 
-    return conv.Convert(content, format)
-}
\ No newline at end of file
+// convertBytes implémente Convert au-dessus de ConvertStream
+func convertBytes(conv Converter, input []byte, outputFormat string) ([]byte, error) {
+    var buf bytes.Buffer
+    opts := ConvertOptions{OutputFormat: canonicalFormat(outputFormat)}
+    if err := conv.ConvertStream(context.Background(), bytes.NewReader(input), &buf, opts); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// contextReader interrompt la lecture dès que le contexte est annulé
+type contextReader struct {
+    ctx context.Context
+    r   io.Reader
+}
+
+func newContextReader(ctx context.Context, r io.Reader) io.Reader {
+    if ctx == nil || ctx.Done() == nil {
+        return r
+    }
+    return &contextReader{ctx: ctx, r: r}
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+    if err := c.ctx.Err(); err != nil {
+        return 0, err
+    }
+    return c.r.Read(p)
+}
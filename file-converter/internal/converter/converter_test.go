@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertFileAliases(t *testing.T) {
+	tests := []struct {
+		format string
+		output string // Fichier produit à côté de l'entrée
+	}{
+		{format: "csv", output: "data.csv"},
+		{format: "CSV", output: "data.csv"},
+		{format: "text", output: "data.txt"},
+		{format: "htm", output: "data.html"},
+		{format: "yml", output: "data.yaml"},
+		{format: "jsonl", output: "data.ndjson"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "data.json")
+			if err := os.WriteFile(input, []byte(`[{"id":1},{"id":2}]`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := ConvertFile(&TextConverter{}, input, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(input), tt.output)); err != nil {
+				t.Errorf("fichier de sortie absent: %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertAliases(t *testing.T) {
+	for _, format := range []string{"text", "htm", "yml", "jsonl", "JSON"} {
+		t.Run(format, func(t *testing.T) {
+			if _, err := (&TextConverter{}).Convert([]byte(`[{"id":1}]`), format); err != nil {
+				t.Errorf("Convert(%s): %v", format, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+// internal/converter/detect.go
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CategoryArchive regroupe les formats d'archive
+const CategoryArchive = "Archive"
+
+// Formats reconnus par la détection qui n'ont pas encore de convertisseur
+func init() {
+	Categories = append(Categories, CategoryArchive)
+
+	RegisterFormat(Format{Name: "tar", Label: "TAR", Category: CategoryArchive,
+		MIMETypes: []string{"application/x-tar"}, Extensions: []string{"tar"}})
+	RegisterFormat(Format{Name: "bmp", Label: "BMP", Category: CategoryImage,
+		MIMETypes: []string{"image/bmp"}, Extensions: []string{"bmp"}})
+	RegisterFormat(Format{Name: "webp", Label: "WebP", Category: CategoryImage,
+		MIMETypes: []string{"image/webp"}, Extensions: []string{"webp"}})
+}
+
+// Niveaux de confiance de la détection
+const (
+	confidenceCertain = 1.0 // Signature binaire non ambiguë
+	confidenceHigh    = 0.9 // Structure complète et valide
+	confidenceMedium  = 0.7 // Structure plausible (contenu tronqué, signature courte)
+	confidenceLow     = 0.5 // Indice faible
+	confidenceGuess   = 0.3 // Ressemblance de surface uniquement
+	confidenceText    = 0.4 // Texte brut, utilisé en dernier recours
+	confidenceMinimum = 0.6 // En dessous, l'extension du fichier est préférée
+	maxDetectLayers   = 4   // Profondeur maximale de compressions imbriquées
+	maxSniffLines     = 20  // Lignes examinées pour les formats tabulaires
+)
+
+// Detection est le résultat de l'analyse d'un contenu
+type Detection struct {
+	Format     string  // Nom canonique du format dans le registre ("" si inconnu)
+	MIME       string  // Type MIME du format
+	Confidence float64 // Entre 0 et 1
+}
+
+// Detect retourne le format le plus probable d'un début de contenu
+func Detect(head []byte) Detection {
+	candidates := DetectAll(head)
+	if len(candidates) == 0 {
+		return Detection{}
+	}
+	return candidates[0]
+}
+
+// DetectAll retourne tous les formats possibles d'un début de contenu,
+// du plus probable au moins probable
+func DetectAll(head []byte) []Detection {
+	var candidates []Detection
+	add := func(format string, confidence float64) {
+		d := Detection{Format: format, Confidence: confidence}
+		if f, ok := LookupFormat(format); ok {
+			d.MIME = f.ContentType()
+		}
+		candidates = append(candidates, d)
+	}
+
+	// Signatures binaires
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b, 0x08}):
+		add("gzip", confidenceCertain)
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		add("zip", confidenceCertain)
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		add("png", confidenceCertain)
+	case bytes.HasPrefix(head, []byte{0xff, 0xd8, 0xff}):
+		add("jpeg", confidenceCertain)
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		add("gif", confidenceCertain)
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		add("webp", confidenceCertain)
+	case isBMP(head):
+		add("bmp", confidenceHigh)
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		add("tar", confidenceCertain)
+	case isZlib(head):
+		add("zlib", confidenceMedium)
+	}
+
+	if text := textHead(head); looksLikeText(text) {
+		candidates = append(candidates, detectText(text)...)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	return candidates
+}
+
+// DetectLayers détecte les couches d'un début de contenu, de la plus externe
+// à la plus interne ("gzip" puis "csv"). Le contenu compressé est décompressé
+// partiellement pour identifier son format. name, s'il est fourni, sert
+// d'indice quand le contenu seul n'est pas concluant.
+func DetectLayers(head []byte, name string) []string {
+	return detectLayers(head, DefaultRegistry.LayersFromFileName(name), 0)
+}
+
+func detectLayers(head []byte, hint []string, depth int) []string {
+	d := Detect(head)
+	if d.Format == "" || (d.Confidence < confidenceMinimum && len(hint) > 0) {
+		return hint
+	}
+	// Un document JSON d'une seule ligne est aussi du NDJSON: l'extension tranche
+	if d.Format == "json" && len(hint) > 0 && hint[0] == "ndjson" {
+		return hint
+	}
+
+	// L'indice n'est utile pour le contenu que s'il décrit la même enveloppe
+	var innerHint []string
+	if len(hint) > 1 && hint[0] == d.Format {
+		innerHint = hint[1:]
+	}
+
+	layers := []string{d.Format}
+	if depth >= maxDetectLayers {
+		return layers
+	}
+
+	switch d.Format {
+	case "gzip", "zlib":
+		if inner := inflateHead(d.Format, head); len(inner) > 0 {
+			return append(layers, detectLayers(inner, innerHint, depth+1)...)
+		}
+		return append(layers, innerHint...)
+	case "zip":
+		// Le nom de la première entrée figure dans l'en-tête local
+		if entry := zipEntryName(head); entry != "" {
+			return append(layers, DefaultRegistry.LayersFromFileName(entry)...)
+		}
+		return append(layers, innerHint...)
+	}
+	return layers
+}
+
+// SniffLayers détecte les couches d'un flux sans le consommer: le lecteur
+// retourné rejoue l'intégralité du contenu
+func SniffLayers(r io.Reader, name string) ([]string, io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffSize)
+	head, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, br, err
+	}
+	return DetectLayers(head, name), br, nil
+}
+
+// inflateHead décompresse autant que possible un début de flux compressé
+func inflateHead(format string, head []byte) []byte {
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	if format == "gzip" {
+		rc, err = gzip.NewReader(bytes.NewReader(head))
+	} else {
+		rc, err = zlib.NewReader(bytes.NewReader(head))
+	}
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+
+	buf := make([]byte, sniffSize)
+	n, _ := io.ReadFull(rc, buf)
+	return buf[:n]
+}
+
+// zipEntryName lit le nom de fichier du premier en-tête local d'une archive ZIP
+func zipEntryName(head []byte) string {
+	if len(head) < 30 || !bytes.HasPrefix(head, []byte("PK\x03\x04")) {
+		return ""
+	}
+	nameLen := int(binary.LittleEndian.Uint16(head[26:28]))
+	if len(head) < 30+nameLen {
+		return ""
+	}
+	return string(head[30 : 30+nameLen])
+}
+
+func isZlib(head []byte) bool {
+	if len(head) < 2 || head[0]&0x0f != 8 || head[0]>>4 > 7 {
+		return false
+	}
+	if (uint16(head[0])<<8|uint16(head[1]))%31 != 0 {
+		return false
+	}
+	// La somme de contrôle seule est trop faible: le flux doit aussi se décompresser
+	zr, err := zlib.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+	_, err = zr.Read(make([]byte, 1))
+	return err == nil || err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+func isBMP(head []byte) bool {
+	if len(head) < 26 || string(head[:2]) != "BM" {
+		return false
+	}
+	// Octets réservés à zéro et en-tête DIB de taille connue
+	if binary.LittleEndian.Uint32(head[6:10]) != 0 {
+		return false
+	}
+	switch binary.LittleEndian.Uint32(head[14:18]) {
+	case 12, 40, 52, 56, 64, 108, 124:
+		return true
+	}
+	return false
+}
+
+// looksLikeText indique si le contenu ne contient pas de caractères de contrôle binaires
+func looksLikeText(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+	control := 0
+	for _, b := range head {
+		switch {
+		case b == 0:
+			return false
+		case b == '\t' || b == '\n' || b == '\r' || b == '\f' || b == '\v':
+		case b < 0x20 || b == 0x7f:
+			control++
+		}
+	}
+	return control*20 <= len(head)
+}
+
+// detectText évalue les formats texte possibles
+func detectText(head []byte) []Detection {
+	var candidates []Detection
+	add := func(format string, confidence float64) {
+		d := Detection{Format: format, Confidence: confidence}
+		if f, ok := LookupFormat(format); ok {
+			d.MIME = f.ContentType()
+		}
+		candidates = append(candidates, d)
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	switch trimmed[0] {
+	case '{', '[':
+		jsonScore, ndjsonScore := scoreJSON(trimmed)
+		add("json", jsonScore)
+		if ndjsonScore > 0 {
+			add("ndjson", ndjsonScore)
+		}
+	case '<':
+		if htmlScore := scoreHTML(trimmed); htmlScore > 0 {
+			add("html", htmlScore)
+		}
+		add("xml", scoreXML(trimmed))
+	default:
+		// Un contenu plus court que la lecture anticipée est complet
+		truncated := len(head) >= sniffSize
+		if dialect, confidence := sniffDialect(trimmed, truncated); confidence > 0 {
+			add(dialectFormat(dialect.Delimiter), confidence)
+		}
+		if yamlScore := scoreYAML(trimmed, truncated); yamlScore > 0 {
+			add("yaml", yamlScore)
+		}
+	}
+
+	// Fichiers de configuration: une section "[nom]" ressemble au début d'un tableau JSON
+	if trimmed[0] != '{' && trimmed[0] != '<' {
+		tomlScore, iniScore := scoreConfig(trimmed, len(head) >= sniffSize)
+		if tomlScore > 0 {
+			add("toml", tomlScore)
+		}
+		if iniScore > 0 {
+			add("ini", iniScore)
+		}
+	}
+
+	add("txt", scoreTXT(trimmed, len(head) >= sniffSize))
+	return candidates
+}
+
+// scoreTXT retourne la confiance pour un texte brut: plus élevée pour un
+// tableau aligné ou pour plusieurs blocs "clé: valeur", qui ne forment pas
+// un document YAML valide dès qu'une clé se répète
+func scoreTXT(data []byte, truncated bool) float64 {
+	layout, blocks := sniffTXTLayout(data, truncated)
+	if layout == TXTTable || (layout == TXTBlocks && blocks > 1) {
+		return confidenceMedium
+	}
+	return confidenceText
+}
+
+// scoreJSON retourne la confiance pour un document JSON et pour du NDJSON
+func scoreJSON(data []byte) (float64, float64) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	values := 0
+	var end int64 // Fin de la dernière valeur lue
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			// Contenu tronqué par la lecture anticipée
+			switch {
+			case values == 0:
+				return confidenceMedium, 0
+			case values == 1 && newlineFollows(data[end:]):
+				// Lignes plus longues que la lecture anticipée: la deuxième
+				// valeur est coupée, mais commence sur une nouvelle ligne
+				return confidenceGuess, confidenceMedium
+			}
+			break
+		}
+		if err != nil {
+			if values > 1 {
+				break
+			}
+			// Commence comme du JSON: une erreur de syntaxe est plus utile qu'un repli en texte
+			return confidenceLow, 0
+		}
+		values++
+		end = decoder.InputOffset()
+	}
+
+	if values <= 1 {
+		return confidenceHigh, 0
+	}
+
+	// Plusieurs valeurs: NDJSON si chaque ligne porte exactement une valeur
+	lines := 0
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines++
+		}
+	}
+	if lines == values || lines == values+1 {
+		return confidenceGuess, confidenceHigh
+	}
+	return confidenceGuess, confidenceLow
+}
+
+// newlineFollows indique si un retour à la ligne précède la prochaine valeur
+func newlineFollows(data []byte) bool {
+	rest := bytes.TrimLeft(data, " \t\r")
+	return len(rest) > 0 && rest[0] == '\n'
+}
+
+// Lignes caractéristiques du YAML en mode bloc: "clé: valeur", "clé:" ou "- élément"
+var (
+	yamlKeyLine  = regexp.MustCompile(`^\s*(- )?[^\s#,:\-"'][^:,]*:(\s|$)`)
+	yamlItemLine = regexp.MustCompile(`^\s*-(\s|$)`)
+)
+
+// scoreYAML retourne la confiance pour un document YAML en mode bloc. Des
+// paires "clé: valeur" à plat ressemblent aussi à du texte: seuls un
+// marqueur de document ou une imbrication donnent une confiance suffisante.
+func scoreYAML(data []byte, truncated bool) float64 {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > maxSniffLines {
+		lines = lines[:maxSniffLines]
+	}
+
+	keys, nested := 0, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case trimmed == "---" || strings.HasPrefix(trimmed, "--- ") || strings.HasPrefix(trimmed, "%YAML"):
+			if keys == 0 && i == 0 {
+				return confidenceHigh
+			}
+		case yamlKeyLine.MatchString(line):
+			keys++
+			if line != strings.TrimLeft(line, " ") {
+				nested = true
+			}
+		case yamlItemLine.MatchString(line):
+			keys++
+		case line != strings.TrimLeft(line, " "):
+			// Suite indentée d'une valeur (bloc "|", liste en ligne...)
+		default:
+			return 0
+		}
+	}
+
+	switch {
+	case keys == 0:
+		return 0
+	case nested:
+		return confidenceMedium
+	}
+	return confidenceLow
+}
+
+// Lignes caractéristiques des fichiers de configuration: "[section]",
+// "[[tableau]]" (TOML) et "clé = valeur"
+var (
+	configSectionLine = regexp.MustCompile(`^\[\[?\s*[^\[\]=]+\]\]?$`)
+	configKeyLine     = regexp.MustCompile(`^[^\s=\[#;"'][^=]*=`)
+)
+
+// scoreConfig retourne la confiance pour un document TOML et pour un fichier
+// INI. Un fichier INI simple est souvent du TOML valide: TOML l'emporte alors,
+// sinon seul INI est proposé.
+func scoreConfig(data []byte, truncated bool) (float64, float64) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	keys, sections, colons, other := 0, 0, 0, 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || trimmed[0] == '#' || trimmed[0] == ';':
+		case configSectionLine.MatchString(trimmed):
+			sections++
+		case configKeyLine.MatchString(trimmed):
+			keys++
+		case strings.Contains(trimmed, ":"):
+			// "clé: valeur" n'est admis en INI qu'à l'intérieur de sections
+			colons++
+		default:
+			other++
+		}
+	}
+	if sections == 0 {
+		other += colons
+	}
+	if keys == 0 {
+		return 0, 0
+	}
+
+	var doc map[string]interface{}
+	if _, err := toml.Decode(strings.Join(lines, "\n"), &doc); err == nil {
+		return confidenceMedium, confidenceLow
+	}
+	if other == 0 {
+		return 0, confidenceMedium
+	}
+	if other < keys {
+		return 0, confidenceLow
+	}
+	return 0, 0
+}
+
+// htmlStart reconnaît le début d'une page ou d'un fragment de tableau HTML,
+// après d'éventuels commentaires
+var htmlStart = regexp.MustCompile(`(?is)^(<!--.*?-->\s*)*<(!doctype\s+html|html|head|body|table)[\s>]`)
+
+// scoreHTML retourne la confiance pour une page HTML; à confiance égale, elle
+// l'emporte sur le XML
+func scoreHTML(data []byte) float64 {
+	if htmlStart.Match(data) {
+		return confidenceHigh
+	}
+	return 0
+}
+
+// scoreXML retourne la confiance pour un document XML
+func scoreXML(data []byte) float64 {
+	if bytes.HasPrefix(data, []byte("<?xml")) {
+		return confidenceHigh
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	elements := 0
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF && elements > 0 {
+				return confidenceHigh - 0.1
+			}
+			if elements > 0 {
+				return confidenceMedium
+			}
+			return confidenceGuess
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			elements++
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectLayersNDJSON(t *testing.T) {
+	long := `{"message":"` + strings.Repeat("x", 3000) + `"}` + "\n"
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{name: "lignes courtes", content: "{\"a\":1}\n{\"a\":2}\n", want: []string{"ndjson"}},
+		{name: "lignes longues", content: strings.Repeat(long, 3), want: []string{"ndjson"}},
+		{name: "lignes longues, extension", file: "a.ndjson", content: strings.Repeat(long, 3), want: []string{"ndjson"}},
+		{name: "une ligne, extension ndjson", file: "a.ndjson", content: long, want: []string{"ndjson"}},
+		{name: "une ligne, extension jsonl", file: "a.jsonl", content: long, want: []string{"ndjson"}},
+		{name: "document long", content: "[" + strings.Repeat(long[:len(long)-1]+",\n", 3) + "{}]", want: []string{"json"}},
+		{name: "document court", file: "a.json", content: `{"a":1}`, want: []string{"json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := []byte(tt.content)
+			if len(head) > sniffSize {
+				head = head[:sniffSize]
+			}
+			if got := DetectLayers(head, tt.file); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectLayers() = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// internal/converter/dialect.go
+package converter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+)
+
+// Dialect décrit la forme d'un fichier délimité (CSV, TSV, PSV...)
+type Dialect struct {
+	Delimiter rune // Séparateur de champs
+	Quote     rune // Caractère de citation
+	Header    bool // La première ligne est un en-tête
+	CRLF      bool // Lignes terminées par \r\n
+}
+
+// Séparateurs essayés par le détecteur, par ordre de préférence
+var dialectDelimiters = []rune{',', ';', '\t', '|'}
+
+// defaultDialect est utilisé quand le contenu ne permet pas de conclure
+var defaultDialect = Dialect{Delimiter: ',', Quote: '"', Header: true}
+
+// delimitedFormat indique si un format est lu et écrit comme un CSV
+func delimitedFormat(format string) bool {
+	return format == "csv" || format == "tsv" || format == "psv"
+}
+
+// formatDelimiter retourne le séparateur propre à un format, 0 pour le CSV
+// dont le séparateur est détecté
+func formatDelimiter(format string) rune {
+	switch format {
+	case "tsv":
+		return '\t'
+	case "psv":
+		return '|'
+	}
+	return 0
+}
+
+// dialectFormat retourne le format correspondant à un séparateur
+func dialectFormat(delimiter rune) string {
+	switch delimiter {
+	case '\t':
+		return "tsv"
+	case '|':
+		return "psv"
+	}
+	return "csv"
+}
+
+// SniffDialect devine le dialecte d'un début de fichier délimité. truncated
+// indique que la dernière ligne peut être incomplète.
+func SniffDialect(head []byte, truncated bool) Dialect {
+	dialect, _ := sniffDialect(head, truncated)
+	return dialect
+}
+
+// sniffDialect retourne le dialecte le plus probable et la confiance associée
+// (0 si aucun séparateur ne donne un tableau d'au moins deux colonnes)
+func sniffDialect(head []byte, truncated bool) (Dialect, float64) {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+	// La dernière ligne peut avoir été tronquée par la lecture anticipée
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > maxSniffLines {
+		lines = lines[:maxSniffLines]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	dialect := defaultDialect
+	dialect.CRLF = bytes.Contains(head, []byte("\r\n"))
+	dialect.Quote = sniffQuote(lines)
+
+	var best [][]string
+	confidence := 0.0
+	for _, delimiter := range dialectDelimiters {
+		records, score := scoreDialect(lines, delimiter, dialect.Quote)
+		// À confiance égale, le premier séparateur de la liste l'emporte
+		if score > confidence {
+			confidence, best = score, records
+			dialect.Delimiter = delimiter
+		}
+	}
+	if confidence == 0 {
+		return dialect, 0
+	}
+	dialect.Header = sniffHeader(best)
+	return dialect, confidence
+}
+
+// sniffQuote retourne l'apostrophe si elle est le seul caractère utilisé pour
+// encadrer des champs, le guillemet sinon
+func sniffQuote(lines []string) rune {
+	count := func(q byte) int {
+		n := 0
+		for _, line := range lines {
+			for i := 0; i < len(line); i++ {
+				if line[i] != q {
+					continue
+				}
+				// Un champ cité commence en début de ligne ou après un séparateur
+				if i == 0 || strings.ContainsRune(",;\t|", rune(line[i-1])) {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if count('"') == 0 && count('\'') > 0 {
+		return '\''
+	}
+	return '"'
+}
+
+// scoreDialect lit les lignes avec un séparateur et retourne les
+// enregistrements et la confiance: élevée si toutes les lignes ont le même
+// nombre de champs (au moins deux)
+func scoreDialect(lines []string, delimiter, quote rune) ([][]string, float64) {
+	swap := quoteSwap(quote)
+	reader := csv.NewReader(swap.reader(strings.NewReader(strings.Join(lines, "\n"))))
+	reader.Comma = swap.rune(delimiter)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, 0
+	}
+
+	fields := len(records[0])
+	if fields < 2 {
+		return nil, 0
+	}
+	for _, record := range records[1:] {
+		if len(record) != fields {
+			return records, confidenceGuess
+		}
+	}
+	if len(records) == 1 {
+		return records, confidenceLow
+	}
+	return records, confidenceHigh - 0.05
+}
+
+// sniffHeader compare la première ligne aux suivantes, colonne par colonne:
+// une colonne dont les valeurs sont des nombres (ou des dates) et dont la
+// première cellule n'en est pas un désigne un en-tête; l'inverse désigne une
+// ligne de données. Sans indice, la première ligne est un en-tête.
+func sniffHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return true
+	}
+	votes := 0
+	for column, first := range records[0] {
+		kind := ""
+		for _, record := range records[1:] {
+			if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+				continue
+			}
+			cell := cellKind(record[column])
+			if kind == "" {
+				kind = cell
+			} else if kind != cell {
+				kind = TypeString
+				break
+			}
+		}
+		if kind == "" || kind == TypeString {
+			continue
+		}
+		if cellKind(first) == kind {
+			votes--
+		} else {
+			votes++
+		}
+	}
+	return votes >= 0
+}
+
+// cellKind classe une cellule: nombre, date ou texte
+func cellKind(cell string) string {
+	cell = strings.TrimSpace(cell)
+	switch {
+	case numberPattern.MatchString(cell):
+		return TypeNumber
+	case numberPattern.MatchString(strings.Replace(cell, ",", ".", 1)):
+		// Décimale à la française: "1,5"
+		return TypeNumber
+	}
+	if _, ok := parseDate(cell); ok {
+		return TypeDate
+	}
+	return TypeString
+}
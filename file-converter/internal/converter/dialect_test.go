@@ -0,0 +1,45 @@
+package converter
+
+import "testing"
+
+func TestSniffDialect(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      string
+		truncated bool
+		want      Dialect
+		known     bool // Un séparateur a été reconnu
+	}{
+		{name: "virgule", head: "nom,age\nA,1\nB,2\n", want: Dialect{Delimiter: ',', Quote: '"', Header: true}, known: true},
+		{name: "point-virgule", head: "a;b;c\nx;1;2\ny;3;4\n", want: Dialect{Delimiter: ';', Quote: '"', Header: true}, known: true},
+		{name: "tabulation", head: "a\tb\nx\t1\n", want: Dialect{Delimiter: '\t', Quote: '"', Header: true}, known: true},
+		{name: "barre", head: "a|b\nx|1\n", want: Dialect{Delimiter: '|', Quote: '"', Header: true}, known: true},
+		{name: "sans en-tête", head: "1,2\n3,4\n", want: Dialect{Delimiter: ',', Quote: '"'}, known: true},
+		{name: "apostrophe", head: "a,b\n'x,y',1\n'z',2\n", want: Dialect{Delimiter: ',', Quote: '\'', Header: true}, known: true},
+		{name: "CRLF", head: "a,b\r\nx,1\r\n", want: Dialect{Delimiter: ',', Quote: '"', Header: true, CRLF: true}, known: true},
+		{name: "virgule décimale", head: "a;b\r\n1,5;2\r\n", want: Dialect{Delimiter: ';', Quote: '"', Header: true, CRLF: true}, known: true},
+		{name: "premier séparateur préféré", head: "a,b;c\n1,2;3\n", want: Dialect{Delimiter: ',', Quote: '"', Header: true}, known: true},
+		{name: "dernière ligne tronquée", head: "a;b\nx;1\ny;2\nz,", truncated: true, want: Dialect{Delimiter: ';', Quote: '"', Header: true}, known: true},
+		{name: "une colonne", head: "x\n", want: defaultDialect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := sniffDialect([]byte(tt.head), tt.truncated)
+			if got != tt.want {
+				t.Errorf("dialecte = %+v, attendu %+v", got, tt.want)
+			}
+			if (confidence > 0) != tt.known {
+				t.Errorf("confiance = %v", confidence)
+			}
+		})
+	}
+}
+
+func TestDialectConversion(t *testing.T) {
+	runTextTests(t, []textTest{
+		{name: "dialecte détecté", input: "a;b\r\n1,5;2\r\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", JSON: JSONOptions{Compact: true}},
+			want: `[{"a":"1,5","b":"2"}]`},
+		{name: "séparateur du format", input: "a\tb\n1\t2\n", opts: ConvertOptions{InputFormat: "tsv", OutputFormat: "psv"},
+			want: "a|b\n1|2\n"},
+	})
+}
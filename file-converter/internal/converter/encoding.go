@@ -0,0 +1,199 @@
+// internal/converter/encoding.go
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encodages détectés sur les entrées texte
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingWindows = "windows-1252" // Latin-1 étendu des exports Windows
+)
+
+// Encodages Unicode nommés directement; les autres sont cherchés dans les
+// registres IANA et WHATWG ("latin1", "iso-8859-15", "cp1252"...)
+var unicodeEncodings = map[string]encoding.Encoding{
+	"utf-8":     unicode.UTF8,
+	"utf8":      unicode.UTF8,
+	"utf-8-bom": unicode.UTF8BOM,
+	"utf-16":    unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16le":  unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf-16be":  unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+}
+
+// lookupEncoding retourne l'encodage désigné par un nom
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if enc, ok := unicodeEncodings[key]; ok {
+		return enc, nil
+	}
+	if enc, err := ianaindex.IANA.Encoding(key); err == nil && enc != nil {
+		return enc, nil
+	}
+	if enc, err := htmlindex.Get(key); err == nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("encodage inconnu: %s", name)
+}
+
+// encodingLabel retourne le nom IANA d'un encodage ("latin1" → "ISO-8859-1"),
+// à déclarer dans un document XML; vide pour l'UTF-8
+func encodingLabel(name string) string {
+	enc, err := lookupEncoding(name)
+	if name == "" || err != nil || enc == unicode.UTF8 {
+		return ""
+	}
+	if label, err := ianaindex.MIME.Name(enc); err == nil && label != "" {
+		return label
+	}
+	return name
+}
+
+// DetectEncoding devine l'encodage d'un début de texte: d'après la marque
+// d'ordre des octets (BOM), puis les octets nuls de l'UTF-16, puis la
+// validité UTF-8. À défaut, le texte est supposé en windows-1252.
+func DetectEncoding(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xef\xbb\xbf")):
+		return EncodingUTF8
+	case bytes.HasPrefix(head, []byte("\xff\xfe")):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(head, []byte("\xfe\xff")):
+		return EncodingUTF16BE
+	}
+
+	// Texte ASCII en UTF-16: un octet sur deux est nul
+	var even, odd int
+	for i, b := range head {
+		if b == 0 {
+			if i%2 == 0 {
+				even++
+			} else {
+				odd++
+			}
+		}
+	}
+	if pairs := len(head) / 2; pairs > 0 {
+		switch {
+		case odd*10 > pairs*3 && even*10 < pairs:
+			return EncodingUTF16LE
+		case even*10 > pairs*3 && odd*10 < pairs:
+			return EncodingUTF16BE
+		}
+	}
+
+	if validUTF8(head, len(head) >= sniffSize) {
+		return EncodingUTF8
+	}
+	return EncodingWindows
+}
+
+// validUTF8 vérifie un début de contenu; s'il est tronqué, le dernier
+// caractère peut être incomplet
+func validUTF8(head []byte, truncated bool) bool {
+	if utf8.Valid(head) {
+		return true
+	}
+	if !truncated {
+		return false
+	}
+	for i := 1; i < utf8.UTFMax && i < len(head); i++ {
+		if utf8.RuneStart(head[len(head)-i]) {
+			return utf8.Valid(head[:len(head)-i])
+		}
+	}
+	return false
+}
+
+// textHead retourne un début de contenu en UTF-8 pour la détection de format:
+// seul l'UTF-16, illisible en l'état, est décodé
+func textHead(head []byte) []byte {
+	name := DetectEncoding(head)
+	if name != EncodingUTF16LE && name != EncodingUTF16BE {
+		return head
+	}
+	enc, _ := lookupEncoding(name)
+	decoded, _, err := transform.Bytes(unicode.BOMOverride(enc.NewDecoder()), head[:len(head)&^1])
+	if err != nil {
+		return head
+	}
+	return decoded
+}
+
+// decodeInput retourne un lecteur UTF-8 du contenu de br, décodé depuis
+// l'encodage name (détecté si vide). La marque d'ordre des octets est retirée.
+func decodeInput(br *bufio.Reader, name string) (*bufio.Reader, error) {
+	head, _ := br.Peek(sniffSize)
+	if name == "" {
+		name = DetectEncoding(head)
+	}
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	if enc == unicode.UTF8 && !bytes.HasPrefix(head, []byte("\xef\xbb\xbf")) {
+		return br, nil
+	}
+	decoder := unicode.BOMOverride(enc.NewDecoder())
+	return bufio.NewReaderSize(transform.NewReader(br, decoder), sniffSize), nil
+}
+
+// encodeOutput retourne un écrivain qui encode le texte UTF-8 produit vers
+// l'encodage name; Close termine l'encodage sans fermer w
+func encodeOutput(w io.Writer, name string) (io.WriteCloser, error) {
+	if name == "" {
+		return nopWriteCloser{w}, nil
+	}
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	if enc == unicode.UTF8 {
+		return nopWriteCloser{w}, nil
+	}
+	return &encodingWriter{w: transform.NewWriter(w, enc.NewEncoder()), name: name}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// encodingWriter précise les erreurs d'encodage (caractère absent de
+// l'encodage de sortie)
+type encodingWriter struct {
+	w    io.WriteCloser
+	name string
+}
+
+func (e *encodingWriter) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("encodage %s: %v", e.name, err)
+	}
+	return n, nil
+}
+
+func (e *encodingWriter) Close() error {
+	if err := e.w.Close(); err != nil {
+		return fmt.Errorf("encodage %s: %v", e.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"vide", "", EncodingUTF8},
+		{"ASCII", "a,b\n1,2\n", EncodingUTF8},
+		{"UTF-8", "nom\nZoé\n", EncodingUTF8},
+		{"BOM UTF-8", "\xef\xbb\xbfa", EncodingUTF8},
+		{"BOM UTF-16LE", "\xff\xfea\x00", EncodingUTF16LE},
+		{"BOM UTF-16BE", "\xfe\xff\x00a", EncodingUTF16BE},
+		{"UTF-16LE sans BOM", "a\x00,\x00b\x00\n\x00", EncodingUTF16LE},
+		{"UTF-16BE sans BOM", "\x00a\x00,\x00b\x00\n", EncodingUTF16BE},
+		{"Latin-1", "caf\xe9", EncodingWindows},
+		{"UTF-8 tronqué", strings.Repeat("a", sniffSize-1) + "\xc3", EncodingUTF8},
+	}
+	for _, tt := range tests {
+		if got := DetectEncoding([]byte(tt.head)); got != tt.want {
+			t.Errorf("%s: DetectEncoding = %s, attendu %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConvertEncoding(t *testing.T) {
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "entrée Latin-1 détectée", input: "nom,ville\nZo\xe9,Orl\xe9ans\n", opts: ConvertOptions{OutputFormat: "json", JSON: compact},
+			want: `[{"nom":"Zoé","ville":"Orléans"}]`},
+		{name: "entrée UTF-16 avec BOM", input: "\xff\xfea\x00\n\x00\xe9\x00\n\x00", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", JSON: compact},
+			want: `[{"a":"é"}]`},
+		{name: "entrée imposée", input: "a\nZo\xe9\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", Encoding: "utf-8", JSON: compact},
+			want: `[{"a":"Zo` + "�" + `"}]`},
+		{name: "sortie Latin-1", input: `[{"nom":"Zoé"}]`, opts: ConvertOptions{OutputFormat: "csv", OutputEncoding: "latin1"},
+			want: "nom\nZo\xe9\n"},
+		{name: "sortie UTF-16", input: `[{"a":"é"}]`, opts: ConvertOptions{OutputFormat: "csv", OutputEncoding: "utf-16"},
+			want: "\xff\xfea\x00\n\x00\xe9\x00\n\x00"},
+		{name: "déclaration XML", input: `[{"a":"é"}]`, opts: ConvertOptions{OutputFormat: "xml", OutputEncoding: "latin1", XML: XMLOptions{Compact: true}},
+			want: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<root><item><field name=\"a\">\xe9</field></item></root>"},
+		{name: "entrée XML déclarée", input: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<r><i><a>\xe9</a></i></r>", opts: ConvertOptions{OutputFormat: "json", JSON: compact},
+			want: `[{"a":"é"}]`},
+		{name: "caractère absent", input: `[{"a":"€"}]`, opts: ConvertOptions{OutputFormat: "csv", OutputEncoding: "latin1"},
+			wantErr: "erreur lors de la finalisation du CSV: encodage latin1"},
+		{name: "encodage inconnu", input: "a\n1\n", opts: ConvertOptions{OutputFormat: "csv", Encoding: "klingon"},
+			wantErr: "encodage inconnu: klingon"},
+	})
+}
@@ -0,0 +1,274 @@
+// internal/converter/flatten.go
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Modes d'aplatissement des valeurs imbriquées pour les sorties à plat (CSV, TXT)
+const (
+	FlattenPath = "path" // Une colonne par valeur: address.city, tags[0]
+	FlattenJSON = "json" // Une cellule JSON par objet ou liste
+	FlattenNone = "none" // Colonnes lues telles quelles; cellule JSON à l'écriture
+)
+
+// Nombre maximal d'éléments recréés pour une liste lors de la reconstruction.
+// Un indice est aussi borné par le nombre de colonnes: Flatten n'écrit pas
+// de trou dans une liste.
+const maxFlattenIndex = 1024
+
+// FlattenOptions règle l'aplatissement à l'écriture et la reconstruction
+// des valeurs imbriquées à la lecture d'un CSV
+type FlattenOptions struct {
+	Mode      string // FlattenPath par défaut
+	Separator string // Séparateur des niveaux d'un chemin, "." par défaut
+}
+
+func (o FlattenOptions) mode() string {
+	if o.Mode == "" {
+		return FlattenPath
+	}
+	return o.Mode
+}
+
+func (o FlattenOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+func (o FlattenOptions) validate() error {
+	switch o.mode() {
+	case FlattenPath, FlattenJSON, FlattenNone:
+	default:
+		return fmt.Errorf("mode d'aplatissement inconnu: %s (path, json ou none)", o.Mode)
+	}
+	if strings.ContainsAny(o.Separator, "[]") {
+		return fmt.Errorf("séparateur de chemin invalide: %q", o.Separator)
+	}
+	return nil
+}
+
+// Flatten remplace les objets et les listes d'un enregistrement par des
+// champs simples. En mode chemin, {"address": {"city": "Paris"}, "tags": ["a"]}
+// devient {"address.city": "Paris", "tags[0]": "a"}; en mode JSON ou none,
+// chaque valeur imbriquée devient une chaîne JSON.
+func Flatten(r *Record, opts FlattenOptions) *Record {
+	out := NewRecord()
+	for _, key := range r.Keys() {
+		value, _ := r.Get(key)
+		if opts.mode() != FlattenPath {
+			if isNested(value) {
+				value = FormatValue(value)
+			}
+			out.Set(key, value)
+			continue
+		}
+		flattenValue(out, key, value, opts.separator())
+	}
+	return out
+}
+
+func flattenValue(out *Record, path string, value interface{}, sep string) {
+	switch v := value.(type) {
+	case *Record:
+		if v.Len() == 0 {
+			out.Set(path, FormatValue(v))
+		}
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			flattenValue(out, path+sep+key, child, sep)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out.Set(path, FormatValue(v))
+		}
+		for i, child := range v {
+			flattenValue(out, fmt.Sprintf("%s[%d]", path, i), child, sep)
+		}
+	default:
+		out.Set(path, value)
+	}
+}
+
+func isNested(value interface{}) bool {
+	switch value.(type) {
+	case *Record, []interface{}:
+		return true
+	}
+	return false
+}
+
+// Unflatten reconstruit les valeurs imbriquées d'un enregistrement aplati
+// par Flatten. Une cellule vide est gardée comme une chaîne vide, sauf dans
+// une liste ("tags[1]"), où elle vient des colonnes d'autres lignes, et sauf
+// si le chemin est occupé par une autre valeur. Si les chemins sont
+// incohérents ("a" et "a.b" à la fois), l'enregistrement est retourné tel
+// quel. Un indice de liste trop grand est une erreur. En mode none, les
+// colonnes ne sont pas interprétées.
+func Unflatten(r *Record, opts FlattenOptions) (*Record, error) {
+	switch opts.mode() {
+	case FlattenNone:
+		return r, nil
+	case FlattenJSON:
+		out := NewRecord()
+		for _, key := range r.Keys() {
+			value, _ := r.Get(key)
+			if text, ok := value.(string); ok {
+				value = parseJSONCell(text)
+			}
+			out.Set(key, value)
+		}
+		return out, nil
+	}
+
+	limit := min(maxFlattenIndex, r.Len())
+	var root interface{} = NewRecord()
+	for _, key := range r.Keys() {
+		value, _ := r.Get(key)
+		path, ok := parsePath(key, opts.separator())
+		if !ok {
+			path = []pathElem{{key: key}}
+		} else if value == "" && pathHasIndex(path) {
+			continue
+		}
+		for _, elem := range path {
+			if elem.isIndex && elem.index >= limit {
+				return nil, fmt.Errorf("erreur lors de la reconstruction des colonnes: indice trop grand dans %q (%d au plus)", key, limit-1)
+			}
+		}
+		next, ok := assignPath(root, path, value)
+		if !ok {
+			if value == "" {
+				continue
+			}
+			return r, nil
+		}
+		root = next
+	}
+	return root.(*Record), nil
+}
+
+// pathHasIndex indique si un chemin traverse une liste
+func pathHasIndex(path []pathElem) bool {
+	for _, elem := range path {
+		if elem.isIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONCell décode une cellule contenant un objet ou une liste JSON
+func parseJSONCell(text string) interface{} {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return text
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil || decoder.More() {
+		return text
+	}
+	return value
+}
+
+// pathElem est un niveau d'un chemin: un nom de champ ou un indice de liste
+type pathElem struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath découpe "address.city" ou "tags[0]"; ok est faux si le chemin
+// n'a qu'un niveau ou s'il est mal formé
+func parsePath(key, sep string) ([]pathElem, bool) {
+	var path []pathElem
+	for _, part := range strings.Split(key, sep) {
+		name := part
+		var indexes []int
+		for strings.HasSuffix(name, "]") {
+			open := strings.LastIndex(name, "[")
+			if open < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(name[open+1 : len(name)-1])
+			// Un indice hors des entiers est trop grand, pas mal formé
+			if (err != nil && !errors.Is(err, strconv.ErrRange)) || index < 0 {
+				return nil, false
+			}
+			indexes = append([]int{index}, indexes...)
+			name = name[:open]
+		}
+		if name == "" {
+			return nil, false
+		}
+		path = append(path, pathElem{key: name})
+		for _, index := range indexes {
+			path = append(path, pathElem{index: index, isIndex: true})
+		}
+	}
+	if len(path) < 2 || path[0].isIndex {
+		return nil, false
+	}
+	return path, true
+}
+
+// assignPath place value au bout de path dans node (nil, *Record ou liste)
+// et retourne le nœud mis à jour; ok est faux en cas de conflit
+func assignPath(node interface{}, path []pathElem, value interface{}) (interface{}, bool) {
+	if len(path) == 0 {
+		switch {
+		case vacant(node):
+			return value, true
+		case value == "":
+			// Une cellule vide ne remplace pas une valeur déjà placée
+			return node, true
+		}
+		return nil, false
+	}
+
+	elem := path[0]
+	if elem.isIndex {
+		list, ok := node.([]interface{})
+		if !vacant(node) && !ok {
+			return nil, false
+		}
+		for len(list) <= elem.index {
+			list = append(list, nil)
+		}
+		child, ok := assignPath(list[elem.index], path[1:], value)
+		if !ok {
+			return nil, false
+		}
+		list[elem.index] = child
+		return list, true
+	}
+
+	record, ok := node.(*Record)
+	if !vacant(node) && !ok {
+		return nil, false
+	}
+	if record == nil {
+		record = NewRecord()
+	}
+	current, _ := record.Get(elem.key)
+	child, ok := assignPath(current, path[1:], value)
+	if !ok {
+		return nil, false
+	}
+	record.Set(elem.key, child)
+	return record, true
+}
+
+// vacant indique si une place peut recevoir une valeur: libre, ou occupée
+// par une cellule vide
+func vacant(node interface{}) bool {
+	return node == nil || node == ""
+}
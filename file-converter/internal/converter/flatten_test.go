@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// flatRecord construit un enregistrement à partir de paires clé, valeur
+func flatRecord(pairs ...string) *Record {
+	r := NewRecord()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		r.Set(pairs[i], pairs[i+1])
+	}
+	return r
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *Record
+		mode   string
+		want   string // Enregistrement attendu en JSON, "" pour une erreur
+	}{
+		{name: "chemins", record: flatRecord("address.city", "Paris", "tags[0]", "a"),
+			want: `{"address":{"city":"Paris"},"tags":["a"]}`},
+		{name: "chemins vides gardés", record: flatRecord("x.y", "", "x.z", ""),
+			want: `{"x":{"y":"","z":""}}`},
+		{name: "colonne simple vide", record: flatRecord("a", "", "b.c", ""),
+			want: `{"a":"","b":{"c":""}}`},
+		{name: "éléments de liste vides ignorés", record: flatRecord("tags[0]", "a", "tags[1]", ""),
+			want: `{"tags":["a"]}`},
+		{name: "cellule vide remplacée", record: flatRecord("address", "", "address.city", "Lyon"),
+			want: `{"address":{"city":"Lyon"}}`},
+		{name: "cellule vide sur un chemin occupé", record: flatRecord("x", "1", "x.y", ""),
+			want: `{"x":"1"}`},
+		{name: "chemins incohérents", record: flatRecord("x", "1", "x.y", "2"),
+			want: `{"x":"1","x.y":"2"}`},
+		{name: "indice dans les colonnes", record: flatRecord("tags[2]", "c", "id", "1", "x", ""),
+			want: `{"tags":[null,null,"c"],"id":"1","x":""}`},
+		{name: "indice au-delà des colonnes", record: flatRecord("tags[65535]", "a", "id", "1")},
+		{name: "indice au-delà du maximum", record: flatRecord("tags[999999999999999999999]", "a")},
+		{name: "indice vide ignoré", record: flatRecord("tags[65535]", "", "id", "1"),
+			want: `{"id":"1"}`},
+		{name: "mode none", record: flatRecord("file.name", "a.txt", "tags[0]", "a"), mode: FlattenNone,
+			want: `{"file.name":"a.txt","tags[0]":"a"}`},
+		{name: "mode json", record: flatRecord("file.name", "a.txt", "tags", `["a"]`), mode: FlattenJSON,
+			want: `{"file.name":"a.txt","tags":["a"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := Unflatten(tt.record, FlattenOptions{Mode: tt.mode})
+			if tt.want == "" {
+				if err == nil {
+					t.Fatal("erreur attendue")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Unflatten() = %s, attendu %s", got, tt.want)
+			}
+		})
+	}
+}
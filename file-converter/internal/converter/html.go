@@ -0,0 +1,243 @@
+// internal/converter/html.go
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlReader lit les tableaux d'une page HTML: une ligne <tr> par
+// enregistrement. La première ligne nomme les colonnes si elle n'a que des
+// cellules <th>; sinon les colonnes sont nommées col1, col2... La page est
+// lue en entier avant le premier enregistrement.
+type htmlReader struct {
+	records []*Record
+}
+
+func newHTMLReader(r io.Reader, opts HTMLOptions) (*htmlReader, error) {
+	doc, err := xhtml.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing HTML: %v", err)
+	}
+
+	tables := htmlTables(doc, nil)
+	switch {
+	case len(tables) == 0:
+		return nil, fmt.Errorf("aucun tableau dans la page HTML")
+	case opts.Table > len(tables):
+		return nil, fmt.Errorf("tableau HTML %d introuvable (%d dans la page)", opts.Table, len(tables))
+	case opts.Table > 0:
+		tables = tables[opts.Table-1 : opts.Table]
+	}
+
+	reader := &htmlReader{}
+	for _, table := range tables {
+		reader.records = append(reader.records, htmlRecords(table)...)
+	}
+	return reader, nil
+}
+
+func (h *htmlReader) Next() (*Record, error) {
+	if len(h.records) == 0 {
+		return nil, io.EOF
+	}
+	item := h.records[0]
+	h.records = h.records[1:]
+	return item, nil
+}
+
+// htmlTables retourne les éléments <table> d'un nœud, dans l'ordre du document
+func htmlTables(n *xhtml.Node, tables []*xhtml.Node) []*xhtml.Node {
+	if n.Type == xhtml.ElementNode && n.DataAtom == atom.Table {
+		tables = append(tables, n)
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		tables = htmlTables(child, tables)
+	}
+	return tables
+}
+
+// htmlRows retourne les lignes d'un tableau, sans celles des tableaux imbriqués
+func htmlRows(n *xhtml.Node, rows []*xhtml.Node) []*xhtml.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != xhtml.ElementNode {
+			continue
+		}
+		switch child.DataAtom {
+		case atom.Tr:
+			rows = append(rows, child)
+		case atom.Thead, atom.Tbody, atom.Tfoot:
+			rows = htmlRows(child, rows)
+		}
+	}
+	return rows
+}
+
+// htmlRecords convertit les lignes d'un tableau en enregistrements
+func htmlRecords(table *xhtml.Node) []*Record {
+	rows := htmlRows(table, nil)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var headers []string
+	if cells, header := htmlCells(rows[0]); header {
+		rows = rows[1:]
+		for i, cell := range cells {
+			if cell == "" {
+				cell = columnName(i)
+			}
+			headers = append(headers, cell)
+		}
+	}
+
+	var records []*Record
+	for _, row := range rows {
+		cells, _ := htmlCells(row)
+		if len(cells) == 0 {
+			continue
+		}
+		item := NewRecord()
+		for i, cell := range cells {
+			if i < len(headers) {
+				item.Set(headers[i], cell)
+			} else {
+				item.Set(columnName(i), cell)
+			}
+		}
+		records = append(records, item)
+	}
+	return records
+}
+
+// htmlCells retourne le texte des cellules d'une ligne, une cellule étant
+// répétée vide autant de fois que son attribut colspan l'indique; header
+// indique que toutes les cellules sont des <th>
+func htmlCells(row *xhtml.Node) (cells []string, header bool) {
+	header = true
+	for cell := row.FirstChild; cell != nil; cell = cell.NextSibling {
+		if cell.Type != xhtml.ElementNode || (cell.DataAtom != atom.Td && cell.DataAtom != atom.Th) {
+			continue
+		}
+		header = header && cell.DataAtom == atom.Th
+		cells = append(cells, htmlText(cell))
+		for span := htmlColspan(cell); span > 1; span-- {
+			cells = append(cells, "")
+		}
+	}
+	return cells, header && len(cells) > 0
+}
+
+// Nombre maximal de colonnes couvertes par une cellule, comme dans les navigateurs
+const maxColspan = 1000
+
+// htmlColspan retourne le nombre de colonnes couvertes par une cellule: 1 si
+// l'attribut colspan est absent ou invalide, au plus maxColspan
+func htmlColspan(cell *xhtml.Node) int {
+	for _, attr := range cell.Attr {
+		if attr.Key == "colspan" {
+			span, err := strconv.Atoi(strings.TrimSpace(attr.Val))
+			switch {
+			case err != nil && !errors.Is(err, strconv.ErrRange), span < 1:
+				return 1
+			case span > maxColspan:
+				return maxColspan
+			}
+			return span
+		}
+	}
+	return 1
+}
+
+// htmlText retourne le texte d'une cellule: les blancs sont réduits comme à
+// l'affichage et chaque <br> devient un retour à la ligne. Les tableaux
+// imbriqués sont lus à part.
+func htmlText(n *xhtml.Node) string {
+	var lines []string
+	var line strings.Builder
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		switch {
+		case n.Type == xhtml.TextNode:
+			line.WriteString(n.Data)
+		case n.Type == xhtml.ElementNode && n.DataAtom == atom.Br:
+			lines = append(lines, strings.Join(strings.Fields(line.String()), " "))
+			line.Reset()
+		case n.Type == xhtml.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style || n.DataAtom == atom.Table):
+		default:
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				walk(child)
+			}
+		}
+	}
+	walk(n)
+	lines = append(lines, strings.Join(strings.Fields(line.String()), " "))
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// htmlWriter écrit un tableau HTML: les en-têtes dans <thead>, une ligne
+// <tr> par enregistrement dans <tbody>. Comme pour le CSV, les
+// enregistrements sont gardés en mémoire si les colonnes ne sont pas
+// connues d'avance.
+type htmlWriter struct {
+	w       io.Writer
+	table   Table
+	fixed   bool
+	started bool
+}
+
+func newHTMLWriter(w io.Writer, columns []string) *htmlWriter {
+	return &htmlWriter{w: w, table: Table{Columns: columns}, fixed: len(columns) > 0}
+}
+
+func (h *htmlWriter) Write(item *Record) error {
+	if !h.fixed {
+		h.table.Append(item)
+		return nil
+	}
+	return h.writeRow(item)
+}
+
+func (h *htmlWriter) writeRow(item *Record) error {
+	var builder strings.Builder
+	if !h.started {
+		h.started = true
+		builder.WriteString("<table>\n  <thead>\n")
+		writeHTMLRow(&builder, "th", h.table.Columns)
+		builder.WriteString("  </thead>\n  <tbody>\n")
+	}
+	writeHTMLRow(&builder, "td", h.table.Row(item))
+	_, err := io.WriteString(h.w, builder.String())
+	return err
+}
+
+func (h *htmlWriter) Close() error {
+	for _, item := range h.table.Records {
+		if err := h.writeRow(item); err != nil {
+			return err
+		}
+	}
+	if !h.started {
+		return fmt.Errorf("pas de données à convertir")
+	}
+	_, err := io.WriteString(h.w, "  </tbody>\n</table>\n")
+	return err
+}
+
+// writeHTMLRow écrit une ligne de cellules échappées; les retours à la ligne
+// deviennent des <br>
+func writeHTMLRow(builder *strings.Builder, tag string, cells []string) {
+	builder.WriteString("    <tr>")
+	for _, cell := range cells {
+		text := html.EscapeString(strings.ReplaceAll(cell, "\r\n", "\n"))
+		builder.WriteString("<" + tag + ">" + strings.ReplaceAll(text, "\n", "<br>") + "</" + tag + ">")
+	}
+	builder.WriteString("</tr>\n")
+}
@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLColspan(t *testing.T) {
+	tests := []struct {
+		colspan string
+		want    int
+	}{
+		{colspan: "", want: 1},
+		{colspan: "3", want: 3},
+		{colspan: " 2 ", want: 2},
+		{colspan: "0", want: 1},
+		{colspan: "-4", want: 1},
+		{colspan: "abc", want: 1},
+		{colspan: "1000", want: 1000},
+		{colspan: "1000000000", want: maxColspan},
+		{colspan: "99999999999999999999999", want: maxColspan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.colspan, func(t *testing.T) {
+			doc := `<table><tr><td>a</td></tr></table>`
+			if tt.colspan != "" {
+				doc = `<table><tr><td colspan="` + tt.colspan + `">a</td></tr></table>`
+			}
+			reader, err := newHTMLReader(strings.NewReader(doc), HTMLOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			item, err := reader.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(item.Keys()); got != tt.want {
+				t.Errorf("colspan=%q: %d colonnes, attendu %d", tt.colspan, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,44 +2,77 @@
 package converter
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
 )
 
 type ImageConverter struct{}
 
-// GetSupportedFormats implements Converter.
+// Formats supportés pour les images
+var ImageFormats = []SupportedFormat{
+	{"JPEG", "jpeg", "image/jpeg"},
+	{"PNG", "png", "image/png"},
+	{"GIF", "gif", "image/gif"},
+}
+
+func init() {
+	RegisterFormat(Format{Name: "jpeg", Label: "JPEG", Category: CategoryImage, Aliases: []string{"jpg"},
+		MIMETypes: []string{"image/jpeg"}, Extensions: []string{"jpg", "jpeg"}})
+	RegisterFormat(Format{Name: "png", Label: "PNG", Category: CategoryImage,
+		MIMETypes: []string{"image/png"}, Extensions: []string{"png"}})
+	RegisterFormat(Format{Name: "gif", Label: "GIF", Category: CategoryImage,
+		MIMETypes: []string{"image/gif"}, Extensions: []string{"gif"}})
+
+	imageFormats := []string{"jpeg", "png", "gif"}
+	RegisterConverter(Registration{
+		Name:    "image",
+		Inputs:  imageFormats,
+		Outputs: imageFormats,
+		New:     func() Converter { return &ImageConverter{} },
+	})
+}
+
+// GetSupportedFormats retourne les formats supportés
 func (i *ImageConverter) GetSupportedFormats() []SupportedFormat {
-	panic("unimplemented")
+	return ImageFormats
 }
 
 func (i *ImageConverter) Convert(input []byte, outputFormat string) ([]byte, error) {
+	return convertBytes(i, input, outputFormat)
+}
+
+// ConvertStream décode l'image lue dans r et l'encode dans w
+func (i *ImageConverter) ConvertStream(ctx context.Context, r io.Reader, w io.Writer, opts ConvertOptions) error {
+	opts.OutputFormat = canonicalFormat(opts.OutputFormat)
+	switch opts.OutputFormat {
+	case "jpeg", "png", "gif":
+	default:
+		return fmt.Errorf("format d'image non supporté: %s", opts.OutputFormat)
+	}
+
 	// Décoder l'image d'entrée
-	img, format, err := image.Decode(bytes.NewReader(input))
+	img, _, err := image.Decode(newContextReader(ctx, r))
 	if err != nil {
-		return nil, fmt.Errorf("erreur de décodage de l'image: %v", err)
+		return fmt.Errorf("erreur de décodage de l'image: %v", err)
 	}
-	fmt.Printf("Format d'entrée détecté : %s\n", format)
 
-	buf := new(bytes.Buffer)
-	switch outputFormat {
+	switch opts.OutputFormat {
 	case "jpeg":
-		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
 	case "png":
-		err = png.Encode(buf, img)
+		err = png.Encode(w, img)
 	case "gif":
-		err = gif.Encode(buf, img, &gif.Options{NumColors: 256})
-	default:
-		return nil, fmt.Errorf("format d'image non supporté: %s", outputFormat)
+		err = gif.Encode(w, img, &gif.Options{NumColors: 256})
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("erreur d'encodage de l'image: %v", err)
+		return fmt.Errorf("erreur d'encodage de l'image: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
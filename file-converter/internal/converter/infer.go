@@ -0,0 +1,223 @@
+// internal/converter/infer.go
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types de colonnes reconnus par un schéma
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeDate    = "date"
+)
+
+var columnTypes = []string{TypeString, TypeInteger, TypeNumber, TypeBoolean, TypeDate}
+
+// TypeOptions règle le typage des valeurs lues sous forme de texte
+type TypeOptions struct {
+	Infer  bool              // Déduit le type des valeurs CSV, XML, TXT et INI
+	Schema map[string]string // Type imposé par colonne ("age": "integer"), même sans Infer
+}
+
+func (o TypeOptions) enabled() bool {
+	return o.Infer || len(o.Schema) > 0
+}
+
+func (o TypeOptions) validate() error {
+	for _, column := range schemaColumns(o.Schema) {
+		if kind := o.Schema[column]; !containsFormat(columnTypes, kind) {
+			return fmt.Errorf("type inconnu pour la colonne %s: %s (%s)", column, kind, strings.Join(columnTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// ParseSchema lit un schéma "age=integer,actif=boolean" (ou "age:integer")
+func ParseSchema(spec string) (map[string]string, error) {
+	schema := make(map[string]string)
+	for _, part := range strings.Split(spec, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		sep := "="
+		if !strings.Contains(part, sep) {
+			sep = ":"
+		}
+		column, kind, ok := strings.Cut(part, sep)
+		if !ok {
+			return nil, fmt.Errorf("schéma invalide: %q (colonne=type attendu)", part)
+		}
+		schema[strings.TrimSpace(column)] = strings.ToLower(strings.TrimSpace(kind))
+	}
+	return schema, nil
+}
+
+// Date est une date ou un horodatage ISO 8601 reconnu à la lecture. Elle est
+// réécrite telle qu'elle a été lue; en JSON, c'est une chaîne.
+type Date struct {
+	time.Time
+	Text string
+}
+
+// String retourne le texte d'origine
+func (d Date) String() string {
+	return d.Text
+}
+
+// MarshalJSON encode la date comme la chaîne d'origine
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Text)
+}
+
+// Formats de date reconnus, du plus courant au plus précis
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+func parseDate(text string) (Date, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return Date{Time: t, Text: text}, true
+		}
+	}
+	return Date{}, false
+}
+
+// Nombres au format JSON: pas de zéro en tête ("007" reste une chaîne)
+var (
+	integerPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	numberPattern  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// inferValue devine le type d'une valeur texte: entier ou décimal, booléen,
+// null (vide ou "null"), date ISO, sinon chaîne
+func inferValue(text string) interface{} {
+	switch text {
+	case "", "null", "NULL":
+		return nil
+	case "true", "TRUE", "True":
+		return true
+	case "false", "FALSE", "False":
+		return false
+	}
+	if numberPattern.MatchString(text) {
+		return json.Number(text)
+	}
+	if date, ok := parseDate(text); ok {
+		return date
+	}
+	return text
+}
+
+// castValue convertit une valeur vers le type imposé par le schéma
+func castValue(value interface{}, kind string) (interface{}, error) {
+	if value == nil || isNested(value) {
+		return value, nil
+	}
+	if kind == TypeString {
+		return FormatValue(value), nil
+	}
+	text := strings.TrimSpace(FormatValue(value))
+	if text == "" {
+		return nil, nil
+	}
+
+	switch kind {
+	case TypeInteger:
+		if integerPattern.MatchString(text) {
+			return json.Number(text), nil
+		}
+	case TypeNumber:
+		if numberPattern.MatchString(text) {
+			return json.Number(text), nil
+		}
+	case TypeBoolean:
+		switch strings.ToLower(text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case TypeDate:
+		if date, ok := parseDate(text); ok {
+			return date, nil
+		}
+	}
+	return nil, fmt.Errorf("valeur %q invalide pour le type %s", text, kind)
+}
+
+// applyTypes type les valeurs d'un enregistrement. Les colonnes du schéma
+// sont désignées par leur chemin ("address.zip"), sans indice de liste.
+// infer indique que les chaînes viennent d'un format sans types.
+func (o TypeOptions) applyTypes(r *Record, sep string, infer bool) (*Record, error) {
+	out := NewRecord()
+	for _, key := range r.Keys() {
+		value, _ := r.Get(key)
+		typed, err := o.typeValue(value, key, sep, infer)
+		if err != nil {
+			return nil, err
+		}
+		out.Set(key, typed)
+	}
+	return out, nil
+}
+
+func (o TypeOptions) typeValue(value interface{}, path, sep string, infer bool) (interface{}, error) {
+	switch v := value.(type) {
+	case *Record:
+		out := NewRecord()
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			typed, err := o.typeValue(child, path+sep+key, sep, infer)
+			if err != nil {
+				return nil, err
+			}
+			out.Set(key, typed)
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			typed, err := o.typeValue(child, path, sep, infer)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = typed
+		}
+		return out, nil
+	}
+
+	if kind, ok := o.Schema[path]; ok {
+		typed, err := castValue(value, kind)
+		if err != nil {
+			return nil, fmt.Errorf("colonne %s: %v", path, err)
+		}
+		return typed, nil
+	}
+	if text, ok := value.(string); ok && infer && o.Infer {
+		return inferValue(text), nil
+	}
+	return value, nil
+}
+
+// schemaColumns retourne les colonnes d'un schéma, triées
+func schemaColumns(schema map[string]string) []string {
+	columns := make([]string, 0, len(schema))
+	for column := range schema {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInferValue(t *testing.T) {
+	tests := []struct {
+		text string
+		want interface{}
+	}{
+		{"", nil},
+		{"null", nil},
+		{"true", true},
+		{"FALSE", false},
+		{"42", json.Number("42")},
+		{"-1.5e3", json.Number("-1.5e3")},
+		{"007", "007"},
+		{"1.", "1."},
+		{"oui", "oui"},
+	}
+	for _, tt := range tests {
+		if got := inferValue(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("inferValue(%q) = %#v, attendu %#v", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{"2024-01-02", "2024-01-02T10:00:00", "2024-01-02T10:00:00+02:00"} {
+		date, ok := inferValue(text).(Date)
+		if !ok || date.String() != text {
+			t.Errorf("inferValue(%q) = %#v, attendu une date", text, inferValue(text))
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{spec: "age=integer, actif:Boolean", want: map[string]string{"age": "integer", "actif": "boolean"}},
+		{spec: "", want: map[string]string{}},
+		{spec: "age", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSchema(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSchema(%q) erreur = %v", tt.spec, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSchema(%q) = %v, attendu %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestConvertTypes(t *testing.T) {
+	const csvInput = "id,age,prix,actif,vide,date,code\n1,30,1.5,true,,2024-01-02,007\n"
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "sans typage", input: "id,code\n1,007\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", JSON: compact},
+			want: `[{"id":"1","code":"007"}]`},
+		{name: "inférence CSV", input: csvInput, opts: ConvertOptions{OutputFormat: "json", JSON: compact, Types: TypeOptions{Infer: true}},
+			want: `[{"id":1,"age":30,"prix":1.5,"actif":true,"vide":null,"date":"2024-01-02","code":"007"}]`},
+		{name: "schéma seul", input: "id,age,code\n1,30,007\n", opts: ConvertOptions{OutputFormat: "json", JSON: compact,
+			Types: TypeOptions{Schema: map[string]string{"age": TypeNumber, "code": TypeString}}},
+			want: `[{"id":"1","age":30,"code":"007"}]`},
+		{name: "schéma prioritaire", input: "id,code\n1,007\n", opts: ConvertOptions{OutputFormat: "json", JSON: compact,
+			Types: TypeOptions{Infer: true, Schema: map[string]string{"id": TypeString}}},
+			want: `[{"id":"1","code":"007"}]`},
+		{name: "inférence XML", input: "<r><i><n>1</n><b>true</b></i></r>", opts: ConvertOptions{OutputFormat: "json", JSON: compact, Types: TypeOptions{Infer: true}},
+			want: `[{"n":1,"b":true}]`},
+		{name: "valeur invalide", input: "age\nabc\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json",
+			Types: TypeOptions{Schema: map[string]string{"age": TypeInteger}}},
+			wantErr: `colonne age: valeur "abc" invalide pour le type integer`},
+		{name: "type inconnu", input: "age\n1\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json",
+			Types: TypeOptions{Schema: map[string]string{"age": "entier"}}},
+			wantErr: "type inconnu pour la colonne age: entier"},
+	})
+}
@@ -0,0 +1,503 @@
+// internal/converter/info.go
+package converter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// FileInfo décrit un fichier analysé par Inspect
+type FileInfo struct {
+	Name       string       `json:"name,omitempty"`
+	Format     string       `json:"format"`
+	Layers     []string     `json:"layers,omitempty"`
+	MIME       string       `json:"mime"`
+	Confidence float64      `json:"confidence"`
+	Size       int64        `json:"size"`
+	Encoding   string       `json:"encoding,omitempty"`
+	Table      *TableInfo   `json:"table,omitempty"`
+	JSON       *JSONInfo    `json:"json,omitempty"`
+	XML        *XMLInfo     `json:"xml,omitempty"`
+	Text       *TextInfo    `json:"text,omitempty"`
+	Image      *ImageInfo   `json:"image,omitempty"`
+	Archive    *ArchiveInfo `json:"archive,omitempty"`
+}
+
+// TableInfo décrit un contenu tabulaire (CSV, TSV, PSV) et son dialecte
+type TableInfo struct {
+	Columns   []string `json:"columns"`
+	Rows      int      `json:"rows"`
+	Delimiter string   `json:"delimiter"`
+	Quote     string   `json:"quote"`
+	Header    bool     `json:"header"`
+	CRLF      bool     `json:"crlf,omitempty"`
+}
+
+// JSONInfo décrit la forme d'un document JSON
+type JSONInfo struct {
+	Type   string   `json:"type"`             // "array", "object", "string", "number", "boolean", "null"
+	Length int      `json:"length,omitempty"` // Nombre d'éléments d'un tableau ou de lignes NDJSON
+	Keys   []string `json:"keys,omitempty"`   // Clés de l'objet, ou de tous les objets du tableau
+}
+
+// XMLInfo décrit la structure d'un document XML
+type XMLInfo struct {
+	Root     string   `json:"root"`
+	Children int      `json:"children"`
+	Elements []string `json:"elements,omitempty"` // Noms distincts des enfants directs
+}
+
+// TextInfo décrit un texte brut
+type TextInfo struct {
+	Lines  int    `json:"lines"`
+	Layout string `json:"layout"` // block, table ou lines
+}
+
+// ImageInfo décrit une image
+type ImageInfo struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	ColorModel string `json:"color_model"`
+}
+
+// ArchiveInfo décrit le contenu d'une archive ou d'un fichier compressé
+type ArchiveInfo struct {
+	Entries          []ArchiveEntry `json:"entries"`
+	CompressedSize   int64          `json:"compressed_size"`
+	UncompressedSize int64          `json:"uncompressed_size"`
+	Ratio            float64        `json:"ratio"`
+}
+
+// ArchiveEntry est un fichier contenu dans une archive
+type ArchiveEntry struct {
+	Name           string `json:"name"`
+	Size           int64  `json:"size"`
+	CompressedSize int64  `json:"compressed_size,omitempty"`
+}
+
+// Inspect analyse un contenu et retourne son format et ses caractéristiques.
+// Le contenu est lu en entier au fil de l'eau, sauf les archives ZIP qui
+// nécessitent un accès aléatoire.
+func Inspect(r io.Reader, name string) (*FileInfo, error) {
+	counter := &countingReader{r: r}
+	br := bufio.NewReaderSize(counter, sniffSize)
+	head, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("erreur de lecture: %v", err)
+	}
+
+	info := &FileInfo{Name: name, Layers: DetectLayers(head, name)}
+	d := Detect(head)
+	if len(info.Layers) > 0 {
+		info.Format = info.Layers[0]
+		info.Confidence = d.Confidence
+		if d.Format != info.Format {
+			// Format déduit de l'extension uniquement
+			info.Confidence = 0
+		}
+		if f, ok := LookupFormat(info.Format); ok {
+			info.MIME = f.ContentType()
+		}
+	}
+
+	if err := inspectContent(info, info.Layers, br); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return nil, fmt.Errorf("erreur de lecture: %v", err)
+	}
+	info.Size = counter.n
+
+	// Le fichier entier constitue le flux compressé
+	if info.Archive != nil && (info.Format == "gzip" || info.Format == "zlib") {
+		info.Archive.CompressedSize = info.Size
+		info.Archive.Ratio = CompressionRatio(info.Archive.UncompressedSize, info.Size)
+	}
+	return info, nil
+}
+
+// inspectContent remplit les détails propres au format de la couche externe
+func inspectContent(info *FileInfo, layers []string, r io.Reader) error {
+	if len(layers) == 0 {
+		return nil
+	}
+
+	// Un contenu texte est analysé une fois décodé en UTF-8
+	if f, ok := LookupFormat(layers[0]); ok && f.Category == CategoryText {
+		br := bufio.NewReaderSize(r, sniffSize)
+		head, _ := br.Peek(sniffSize)
+		info.Encoding = DetectEncoding(head)
+		decoded, err := decodeInput(br, info.Encoding)
+		if err != nil {
+			return err
+		}
+		r = decoded
+	}
+
+	switch layers[0] {
+	case "gzip", "zlib":
+		var (
+			rc        io.ReadCloser
+			entryName string
+			err       error
+		)
+		if layers[0] == "gzip" {
+			var gr *gzip.Reader
+			gr, err = gzip.NewReader(r)
+			if err == nil {
+				rc, entryName = gr, gr.Name
+			}
+		} else {
+			rc, err = zlib.NewReader(r)
+		}
+		if err != nil {
+			return fmt.Errorf("erreur d'ouverture %s: %v", layers[0], err)
+		}
+		defer rc.Close()
+
+		inner := &countingReader{r: rc}
+		if err := inspectContent(info, layers[1:], inner); err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, inner); err != nil {
+			return fmt.Errorf("erreur de décompression %s: %v", layers[0], err)
+		}
+
+		// Une archive tar interne a déjà listé ses entrées
+		if info.Archive == nil {
+			info.Archive = &ArchiveInfo{Entries: []ArchiveEntry{{Name: entryName, Size: inner.n}}}
+		}
+		info.Archive.UncompressedSize = inner.n
+	case "zip":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("erreur de lecture zip: %v", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("erreur d'ouverture zip: %v", err)
+		}
+		archive := &ArchiveInfo{}
+		var compressed int64
+		for _, f := range zr.File {
+			archive.Entries = append(archive.Entries, ArchiveEntry{
+				Name:           f.Name,
+				Size:           int64(f.UncompressedSize64),
+				CompressedSize: int64(f.CompressedSize64),
+			})
+			archive.UncompressedSize += int64(f.UncompressedSize64)
+			compressed += int64(f.CompressedSize64)
+		}
+		archive.CompressedSize = int64(len(data))
+		archive.Ratio = CompressionRatio(archive.UncompressedSize, compressed)
+		info.Archive = archive
+	case "tar":
+		tr := tar.NewReader(r)
+		archive := &ArchiveInfo{}
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("erreur de lecture tar: %v", err)
+			}
+			archive.Entries = append(archive.Entries, ArchiveEntry{Name: hdr.Name, Size: hdr.Size})
+			archive.UncompressedSize += hdr.Size
+		}
+		info.Archive = archive
+	case "csv", "tsv", "psv":
+		br := bufio.NewReaderSize(r, sniffSize)
+		head, _ := br.Peek(sniffSize)
+		dialect := SniffDialect(head, len(head) >= sniffSize)
+		opts := CSVOptions{Delimiter: formatDelimiter(layers[0])}.withDialect(dialect)
+
+		swap := quoteSwap(opts.quote())
+		reader := csv.NewReader(swap.reader(br))
+		reader.Comma = swap.rune(opts.delimiter())
+		reader.FieldsPerRecord = -1
+		table := &TableInfo{
+			Delimiter: string(opts.delimiter()),
+			Quote:     string(opts.quote()),
+			Header:    !opts.NoHeader,
+			CRLF:      dialect.CRLF,
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("erreur lors du parsing CSV: %v", err)
+			}
+			if table.Columns == nil {
+				if table.Header {
+					table.Columns = swap.strings(record)
+					continue
+				}
+				for i := range record {
+					table.Columns = append(table.Columns, columnName(i))
+				}
+			}
+			table.Rows++
+		}
+		info.Table = table
+	case "json":
+		jsonInfo, err := inspectJSON(r)
+		if err != nil {
+			return err
+		}
+		info.JSON = jsonInfo
+	case "ndjson":
+		jsonInfo := &JSONInfo{Type: "array"}
+		var keys keyUnion
+		decoder := json.NewDecoder(r)
+		for {
+			var item json.RawMessage
+			err := decoder.Decode(&item)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("erreur lors du parsing NDJSON: %v", err)
+			}
+			if line, err := inspectJSON(bytes.NewReader(item)); err == nil {
+				keys.add(line.Keys)
+			}
+			jsonInfo.Length++
+		}
+		jsonInfo.Keys = keys.keys
+		info.JSON = jsonInfo
+	case "xml":
+		xmlInfo, err := inspectXML(r)
+		if err != nil {
+			return err
+		}
+		info.XML = xmlInfo
+	case "txt":
+		br := bufio.NewReaderSize(r, sniffSize)
+		head, _ := br.Peek(sniffSize)
+		layout, _ := sniffTXTLayout(head, len(head) >= sniffSize)
+		scanner := newLineScanner(br)
+		text := &TextInfo{Layout: layout}
+		for scanner.Scan() {
+			text.Lines++
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("erreur lors de la lecture du texte: %v", err)
+		}
+		info.Text = text
+	case "png", "jpeg", "gif":
+		cfg, _, err := image.DecodeConfig(r)
+		if err != nil {
+			return fmt.Errorf("erreur de décodage de l'image: %v", err)
+		}
+		info.Image = &ImageInfo{Width: cfg.Width, Height: cfg.Height, ColorModel: colorModelName(cfg.ColorModel)}
+	}
+	return nil
+}
+
+// inspectJSON détermine la forme de la valeur JSON de premier niveau
+func inspectJSON(r io.Reader) (*JSONInfo, error) {
+	decoder := json.NewDecoder(r)
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+	}
+
+	info := &JSONInfo{}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			info.Type = "object"
+			keys, err := objectKeys(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+			}
+			info.Keys = keys
+			return info, nil
+		}
+		info.Type = "array"
+		// Les clés de tous les objets du tableau résument sa structure
+		var keys keyUnion
+		for decoder.More() {
+			tok, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+			}
+			if delim, ok := tok.(json.Delim); ok && delim == '{' {
+				item, err := objectKeys(decoder)
+				if err != nil {
+					return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+				}
+				keys.add(item)
+			} else if ok {
+				if err := skipJSON(decoder); err != nil {
+					return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+				}
+			}
+			info.Length++
+		}
+		info.Keys = keys.keys
+	case string:
+		info.Type = "string"
+	case float64, json.Number:
+		info.Type = "number"
+	case bool:
+		info.Type = "boolean"
+	case nil:
+		info.Type = "null"
+	}
+	return info, nil
+}
+
+// keyUnion accumule les clés de plusieurs objets, dans l'ordre d'apparition
+type keyUnion struct {
+	keys []string
+	seen map[string]bool
+}
+
+func (u *keyUnion) add(keys []string) {
+	if u.seen == nil {
+		u.seen = make(map[string]bool)
+	}
+	for _, key := range keys {
+		if !u.seen[key] {
+			u.seen[key] = true
+			u.keys = append(u.keys, key)
+		}
+	}
+}
+
+// objectKeys lit les clés d'un objet JSON dont l'accolade ouvrante a été consommée
+func objectKeys(decoder *json.Decoder) ([]string, error) {
+	var keys []string
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, fmt.Sprint(tok))
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+	}
+	_, err := decoder.Token()
+	return keys, err
+}
+
+// skipJSON consomme la fin d'un tableau ou d'un objet dont le délimiteur ouvrant a été lu
+func skipJSON(decoder *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		tok, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '[' || delim == '{' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+	}
+	return nil
+}
+
+// inspectXML retrouve l'élément racine et ses enfants directs
+func inspectXML(r io.Reader) (*XMLInfo, error) {
+	decoder := xml.NewDecoder(r)
+	// Comme à la conversion, un document peut déclarer un autre encodage que l'UTF-8
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		enc, err := lookupEncoding(label)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	info := &XMLInfo{}
+	seen := make(map[string]bool)
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors du parsing XML: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 1:
+				info.Root = t.Name.Local
+			case 2:
+				info.Children++
+				if !seen[t.Name.Local] {
+					seen[t.Name.Local] = true
+					info.Elements = append(info.Elements, t.Name.Local)
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return info, nil
+}
+
+// colorModelName retourne un nom lisible pour un modèle de couleurs
+func colorModelName(model color.Model) string {
+	switch model {
+	case color.RGBAModel:
+		return "RGBA"
+	case color.RGBA64Model:
+		return "RGBA64"
+	case color.NRGBAModel:
+		return "NRGBA"
+	case color.NRGBA64Model:
+		return "NRGBA64"
+	case color.AlphaModel:
+		return "Alpha"
+	case color.Alpha16Model:
+		return "Alpha16"
+	case color.GrayModel:
+		return "Gray"
+	case color.Gray16Model:
+		return "Gray16"
+	case color.YCbCrModel:
+		return "YCbCr"
+	case color.NYCbCrAModel:
+		return "NYCbCrA"
+	case color.CMYKModel:
+		return "CMYK"
+	}
+	if palette, ok := model.(color.Palette); ok {
+		return fmt.Sprintf("Palette (%d couleurs)", len(palette))
+	}
+	return "inconnu"
+}
+
+// countingReader compte les octets lus
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInspectJSONKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		input  string
+		want   []string
+		length int
+	}{
+		{name: "objet", file: "a.json", input: `{"b":1,"a":2}`, want: []string{"b", "a"}},
+		{name: "tableau homogène", file: "a.json", input: `[{"id":1,"nom":"a"},{"id":2,"nom":"b"}]`,
+			want: []string{"id", "nom"}, length: 2},
+		{name: "tableau hétérogène", file: "a.json", input: `[{"id":1},{"nom":"b","id":2},[1],3,{"age":4}]`,
+			want: []string{"id", "nom", "age"}, length: 5},
+		{name: "ndjson hétérogène", file: "a.ndjson", input: "{\"id\":1}\n{\"nom\":\"b\"}\n{\"id\":3,\"age\":4}\n",
+			want: []string{"id", "nom", "age"}, length: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Inspect(strings.NewReader(tt.input), tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.JSON == nil {
+				t.Fatalf("pas d'information JSON pour %s (format %s)", tt.file, info.Format)
+			}
+			if !reflect.DeepEqual(info.JSON.Keys, tt.want) {
+				t.Errorf("Keys = %v, attendu %v", info.JSON.Keys, tt.want)
+			}
+			if info.JSON.Length != tt.length {
+				t.Errorf("Length = %d, attendu %d", info.JSON.Length, tt.length)
+			}
+		})
+	}
+}
+
+func TestInspectXMLEncoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "utf-8", input: "<?xml version=\"1.0\"?>\n<catalogue><livre>Zoé</livre><livre/></catalogue>"},
+		{name: "latin-1", input: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<catalogue><livre>Zo\xe9</livre><livre/></catalogue>"},
+		{name: "windows-1252", input: "<?xml version=\"1.0\" encoding=\"windows-1252\"?>\n<catalogue><livre>\x80</livre><livre/></catalogue>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Inspect(strings.NewReader(tt.input), "a.xml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.XML == nil {
+				t.Fatalf("pas d'information XML (format %s)", info.Format)
+			}
+			if info.XML.Root != "catalogue" || info.XML.Children != 2 {
+				t.Errorf("XML = %+v, attendu catalogue avec 2 enfants", *info.XML)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// internal/converter/ini.go
+package converter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// iniReader lit un fichier INI comme un seul enregistrement: les clés avant
+// la première section restent au premier niveau, chaque section devient un
+// objet imbriqué ("[a.b]" donne a → b). Les valeurs sont des chaînes.
+type iniReader struct {
+	scanner *bufio.Scanner
+	done    bool
+}
+
+func (i *iniReader) Next() (*Record, error) {
+	if i.done {
+		return nil, io.EOF
+	}
+	i.done = true
+
+	root := NewRecord()
+	section := root
+	line := 0
+	for i.scanner.Scan() {
+		line++
+		text := strings.TrimSpace(i.scanner.Text())
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+
+		if text[0] == '[' {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("erreur lors du parsing INI: ligne %d: section non fermée", line)
+			}
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if name == "" {
+				return nil, fmt.Errorf("erreur lors du parsing INI: ligne %d: section sans nom", line)
+			}
+			parts, ok := iniSectionPath(name)
+			if !ok {
+				return nil, fmt.Errorf("erreur lors du parsing INI: ligne %d: nom de section mal formé", line)
+			}
+			section = root
+			for _, part := range parts {
+				section = iniSection(section, part)
+			}
+			continue
+		}
+
+		key, value, ok := iniKeyValue(text)
+		if !ok {
+			return nil, fmt.Errorf("erreur lors du parsing INI: ligne %d: clé manquante", line)
+		}
+		section.Set(key, iniUnquote(value))
+	}
+	if err := i.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture INI: %v", err)
+	}
+	return root, nil
+}
+
+// iniKeyValue sépare une ligne "clé = valeur" ou "clé: valeur"; une clé
+// entre guillemets est relue avec ses échappements
+func iniKeyValue(text string) (key, value string, ok bool) {
+	if strings.HasPrefix(text, `"`) {
+		quoted, err := strconv.QuotedPrefix(text)
+		if err != nil {
+			return "", "", false
+		}
+		key, _ = strconv.Unquote(quoted)
+		rest := strings.TrimSpace(text[len(quoted):])
+		if rest != "" && rest[0] != '=' && rest[0] != ':' {
+			return "", "", false
+		}
+		if rest != "" {
+			value = strings.TrimSpace(rest[1:])
+		}
+		return key, value, true
+	}
+
+	key = text
+	if pos := strings.IndexAny(text, "=:"); pos >= 0 {
+		key, value = strings.TrimSpace(text[:pos]), strings.TrimSpace(text[pos+1:])
+	}
+	return key, value, key != ""
+}
+
+// iniSectionPath découpe un nom de section aux points ("a.b" donne a → b),
+// sauf dans les parties entre guillemets
+func iniSectionPath(name string) ([]string, bool) {
+	var parts []string
+	for {
+		name = strings.TrimLeft(name, " \t")
+		var part string
+		if strings.HasPrefix(name, `"`) {
+			quoted, err := strconv.QuotedPrefix(name)
+			if err != nil {
+				return nil, false
+			}
+			part, _ = strconv.Unquote(quoted)
+			name = strings.TrimLeft(name[len(quoted):], " \t")
+			if name != "" && name[0] != '.' {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexByte(name, '.')
+			if end < 0 {
+				end = len(name)
+			}
+			part, name = strings.TrimSpace(name[:end]), name[end:]
+		}
+		parts = append(parts, part)
+		if name == "" {
+			return parts, true
+		}
+		name = name[1:]
+	}
+}
+
+// iniSection retourne la sous-section name de parent, créée si besoin
+func iniSection(parent *Record, name string) *Record {
+	if value, ok := parent.Get(name); ok {
+		if section, ok := value.(*Record); ok {
+			return section
+		}
+	}
+	section := NewRecord()
+	parent.Set(name, section)
+	return section
+}
+
+// iniUnquote retire les guillemets autour d'une valeur
+func iniUnquote(value string) string {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+			return value[1 : len(value)-1]
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// iniWriter écrit un enregistrement unique en INI: les valeurs simples
+// d'abord, puis une section par objet imbriqué. Les listes sont écrites en
+// JSON compact et les valeurs nulles sont omises.
+type iniWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (i *iniWriter) Write(item *Record) error {
+	i.count++
+	if i.count > 1 {
+		return fmt.Errorf("le format INI ne peut représenter qu'un seul enregistrement")
+	}
+	var builder strings.Builder
+	writeINISection(&builder, item, "")
+	_, err := io.WriteString(i.w, builder.String())
+	return err
+}
+
+func (i *iniWriter) Close() error {
+	return nil
+}
+
+func writeINISection(builder *strings.Builder, item *Record, path string) {
+	var sections []string
+	for _, key := range item.Keys() {
+		value, _ := item.Get(key)
+		switch value.(type) {
+		case nil:
+			continue
+		case *Record:
+			sections = append(sections, key)
+			continue
+		}
+		builder.WriteString(iniKey(key, "=:;#[") + " = " + iniQuote(FormatValue(value)) + "\n")
+	}
+
+	for _, key := range sections {
+		value, _ := item.Get(key)
+		name := iniKey(key, ".")
+		if path != "" {
+			name = path + "." + name
+		}
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("[" + name + "]\n")
+		writeINISection(builder, value.(*Record), name)
+	}
+}
+
+// iniKey met entre guillemets une clé ou une partie de nom de section qui
+// ne se relirait pas telle quelle; reserved liste ses caractères réservés
+func iniKey(key, reserved string) string {
+	if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key, reserved+"\"\n\r") {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// iniQuote met entre guillemets une valeur qui ne se relirait pas telle quelle
+func iniQuote(value string) string {
+	if value != strings.TrimSpace(value) || strings.ContainsAny(value, "\n\r") || iniUnquote(value) != value {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// roundTrip convertit input (JSON) vers format puis le relit en JSON
+func roundTrip(t *testing.T, input, format string) (text string, got interface{}) {
+	t.Helper()
+	text, err := convertText(input, ConvertOptions{InputFormat: "json", OutputFormat: format})
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := convertText(text, ConvertOptions{InputFormat: format, OutputFormat: "json"})
+	if err != nil {
+		t.Fatalf("relecture de %q: %v", text, err)
+	}
+	if err := json.Unmarshal([]byte(back), &got); err != nil {
+		t.Fatal(err)
+	}
+	return text, got
+}
+
+func TestINIRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "simple", input: `{"nom":"app","port":"8080"}`},
+		{name: "sections", input: `{"nom":"app","db":{"hôte":"localhost","pool":{"max":"10"}},"log":{}}`},
+		{name: "clés réservées", input: `{"a=b":"1","c:d":"2",";e":"3","#f":"4","[g]":"5","\"h\"":"6","":"7"," i":"8"}`},
+		{name: "sections à point", input: `{"a.b":{"c":"1"},"a":{"b":{"c":"2"}},"x y":{"=":"3"}}`},
+		{name: "valeurs à guillemets", input: `{"a":" espace ","b":"\"cité\"","c":"ligne\nsuite","d":"x = y"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, got := roundTrip(t, tt.input, "ini")
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.input), &want); err != nil {
+				t.Fatal(err)
+			}
+			// Un fichier INI relu est un enregistrement unique
+			if !reflect.DeepEqual(got, []interface{}{want}) {
+				t.Errorf("relecture de %q = %v, attendu %v", text, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// internal/converter/markdown.go
+package converter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// mdWriter écrit un tableau Markdown (GitHub): un en-tête, une ligne de
+// séparation puis une ligne par enregistrement. Les colonnes sont alignées
+// et celles qui ne contiennent que des nombres sont cadrées à droite; les
+// enregistrements sont donc gardés en mémoire jusqu'à la fin.
+type mdWriter struct {
+	w     io.Writer
+	table Table
+}
+
+func (m *mdWriter) Write(item *Record) error {
+	m.table.Append(item)
+	return nil
+}
+
+func (m *mdWriter) Close() error {
+	if len(m.table.Columns) == 0 {
+		return fmt.Errorf("pas de données à convertir")
+	}
+
+	header := make([]string, len(m.table.Columns))
+	widths := make([]int, len(m.table.Columns))
+	numeric := make([]bool, len(m.table.Columns))
+	for i, column := range m.table.Columns {
+		header[i] = mdCell(column)
+		widths[i] = max(utf8.RuneCountInString(header[i]), 3)
+		numeric[i] = len(m.table.Records) > 0
+	}
+	rows := make([][]string, len(m.table.Records))
+	for r, item := range m.table.Records {
+		rows[r] = m.table.Row(item)
+		for i, cell := range rows[r] {
+			if cell != "" && cellKind(cell) != TypeNumber {
+				numeric[i] = false
+			}
+			rows[r][i] = mdCell(cell)
+			widths[i] = max(widths[i], utf8.RuneCountInString(rows[r][i]))
+		}
+	}
+
+	var builder strings.Builder
+	writeMDRow(&builder, header, widths, nil)
+	rule := make([]string, len(widths))
+	for i, width := range widths {
+		rule[i] = strings.Repeat("-", width)
+		if numeric[i] {
+			rule[i] = rule[i][1:] + ":"
+		}
+	}
+	writeMDRow(&builder, rule, widths, nil)
+	for _, row := range rows {
+		writeMDRow(&builder, row, widths, numeric)
+	}
+	_, err := io.WriteString(m.w, builder.String())
+	return err
+}
+
+// writeMDRow écrit une ligne de tableau, les cellules complétées d'espaces
+func writeMDRow(builder *strings.Builder, cells []string, widths []int, right []bool) {
+	builder.WriteString("|")
+	for i, cell := range cells {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if right != nil && right[i] {
+			cell = pad + cell
+		} else {
+			cell += pad
+		}
+		builder.WriteString(" " + cell + " |")
+	}
+	builder.WriteString("\n")
+}
+
+// mdCell échappe une cellule: "|" est précédé d'une barre oblique inverse
+// et les retours à la ligne deviennent des <br>
+func mdCell(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
@@ -0,0 +1,303 @@
+// internal/converter/options.go
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVOptions règle la lecture et l'écriture CSV. À la lecture, le séparateur,
+// le caractère de citation et la présence d'un en-tête sont détectés s'ils
+// ne sont pas imposés.
+type CSVOptions struct {
+	Delimiter rune // Séparateur de champs, ',' par défaut à l'écriture
+	Quote     rune // Caractère de citation, '"' par défaut
+	NoHeader  bool // Pas de ligne d'en-tête: les colonnes sont nommées col1, col2...
+	Header    bool // Première ligne toujours lue comme en-tête
+	CRLF      bool // Lignes terminées par \r\n à l'écriture
+}
+
+// JSONOptions règle l'écriture JSON
+type JSONOptions struct {
+	Indent  string // Indentation, deux espaces par défaut
+	Compact bool   // Sortie sur une seule ligne
+}
+
+// XMLOptions règle les noms d'éléments XML lus et écrits
+type XMLOptions struct {
+	Root string // Élément racine, "root" par défaut; vérifié à la lecture s'il est donné
+	Item string // Élément d'un enregistrement, "item" par défaut; à la lecture, tout enfant de la racine
+	Path string // Éléments lus comme enregistrements ("catalog/book", "//book"), à la place de Root et Item
+
+	Style      string            // Forme des champs écrits: field (défaut), element ou attribute
+	Namespaces map[string]string // Espaces de noms déclarés sur la racine, par préfixe ("" pour celui par défaut)
+	Indent     string            // Indentation, deux espaces par défaut
+	Compact    bool              // Sortie sans indentation
+}
+
+// Formes des champs d'un enregistrement XML écrit
+const (
+	XMLFields     = "field"     // <field name="email" type="...">, relu avec les types
+	XMLElements   = "element"   // <email>...</email>
+	XMLAttributes = "attribute" // <item email="..."/>, les valeurs imbriquées restent des éléments
+)
+
+var xmlStyles = []string{XMLFields, XMLElements, XMLAttributes}
+
+// TXTOptions règle la lecture et l'écriture TXT. À la lecture, la mise en
+// forme (blocs "clé: valeur", tableau aligné ou lignes) est détectée, sauf
+// si des colonnes à largeur fixe sont données.
+type TXTOptions struct {
+	Layout string        // Mise en forme écrite: block (défaut) ou table
+	Widths []FixedColumn // Colonnes à largeur fixe, lues et écrites sans en-tête
+}
+
+// Mises en forme d'un fichier TXT
+const (
+	TXTBlocks = "block" // Un bloc "clé: valeur" par enregistrement
+	TXTTable  = "table" // Tableau aligné, en-tête souligné de tirets
+)
+
+var txtLayouts = []string{TXTBlocks, TXTTable}
+
+// HTMLOptions règle la lecture HTML
+type HTMLOptions struct {
+	Table int // Tableau lu (1 pour le premier de la page); tous si 0
+}
+
+// FixedColumn est une colonne d'un fichier à largeur fixe
+type FixedColumn struct {
+	Name  string
+	Width int // Largeur en caractères
+}
+
+func (o CSVOptions) delimiter() rune {
+	if o.Delimiter == 0 {
+		return ','
+	}
+	return o.Delimiter
+}
+
+func (o CSVOptions) quote() rune {
+	if o.Quote == 0 {
+		return '"'
+	}
+	return o.Quote
+}
+
+// withDialect complète les options non imposées par un dialecte détecté
+func (o CSVOptions) withDialect(d Dialect) CSVOptions {
+	if o.Delimiter == 0 {
+		o.Delimiter = d.Delimiter
+	}
+	if o.Quote == 0 {
+		o.Quote = d.Quote
+	}
+	if !o.Header && !o.NoHeader {
+		o.NoHeader = !d.Header
+	}
+	return o
+}
+
+// readOptions retourne les options de lecture d'un format délimité
+func (o ConvertOptions) readOptions(format string) CSVOptions {
+	csv := o.CSV
+	if csv.Delimiter == 0 {
+		csv.Delimiter = formatDelimiter(format)
+	}
+	return csv
+}
+
+// writeOptions retourne le dialecte d'écriture d'un format délimité
+func (o ConvertOptions) writeOptions(format string) CSVOptions {
+	csv := o.CSVOutput
+	if csv.Delimiter == 0 {
+		csv.Delimiter = formatDelimiter(format)
+	}
+	if csv.Delimiter == 0 {
+		csv.Delimiter = o.CSV.Delimiter
+	}
+	if csv.Quote == 0 {
+		csv.Quote = o.CSV.Quote
+	}
+	csv.NoHeader = csv.NoHeader || o.CSV.NoHeader
+	csv.CRLF = csv.CRLF || o.CSV.CRLF
+	return csv
+}
+
+func (o CSVOptions) validate() error {
+	delimiter, quote := o.delimiter(), o.quote()
+	switch {
+	case o.Header && o.NoHeader:
+		return fmt.Errorf("les options en-tête et sans en-tête sont incompatibles")
+	case delimiter == quote:
+		return fmt.Errorf("le séparateur et le caractère de citation doivent être différents")
+	case delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError:
+		return fmt.Errorf("séparateur CSV invalide: %q", delimiter)
+	case quote == '\r' || quote == '\n' || quote >= utf8.RuneSelf:
+		return fmt.Errorf("caractère de citation invalide: %q (un caractère ASCII est attendu)", quote)
+	}
+	return nil
+}
+
+func (o JSONOptions) indent() string {
+	if o.Indent == "" {
+		return "  "
+	}
+	return o.Indent
+}
+
+func (o XMLOptions) root() string {
+	if o.Root == "" {
+		return "root"
+	}
+	return o.Root
+}
+
+func (o XMLOptions) item() string {
+	if o.Item == "" {
+		return "item"
+	}
+	return o.Item
+}
+
+func (o XMLOptions) style() string {
+	if o.Style == "" {
+		return XMLFields
+	}
+	return o.Style
+}
+
+func (o XMLOptions) indent() string {
+	switch {
+	case o.Compact:
+		return ""
+	case o.Indent == "":
+		return "  "
+	}
+	return o.Indent
+}
+
+func (o XMLOptions) validate() error {
+	for _, name := range []string{o.Root, o.Item} {
+		if strings.ContainsAny(name, " \t\r\n<>&\"'/=") {
+			return fmt.Errorf("nom d'élément XML invalide: %q", name)
+		}
+	}
+	if err := parseXMLPath(o.Path).validate(); err != nil {
+		return err
+	}
+	if !containsFormat(xmlStyles, o.style()) {
+		return fmt.Errorf("forme de champs XML inconnue: %s (%s)", o.Style, strings.Join(xmlStyles, ", "))
+	}
+	for prefix := range o.Namespaces {
+		if prefix != "" && (xmlName(prefix) != prefix || strings.Contains(prefix, ":")) {
+			return fmt.Errorf("préfixe d'espace de noms invalide: %q", prefix)
+		}
+	}
+	if strings.Trim(o.Indent, " \t") != "" {
+		return fmt.Errorf("indentation XML invalide: %q", o.Indent)
+	}
+	return nil
+}
+
+func (o TXTOptions) layout() string {
+	if o.Layout == "" {
+		return TXTBlocks
+	}
+	return o.Layout
+}
+
+func (o TXTOptions) validate() error {
+	if !containsFormat(txtLayouts, o.layout()) {
+		return fmt.Errorf("mise en forme TXT inconnue: %s (%s)", o.Layout, strings.Join(txtLayouts, ", "))
+	}
+	for _, column := range o.Widths {
+		if column.Name == "" || column.Width <= 0 {
+			return fmt.Errorf("colonne à largeur fixe invalide: %q (%d)", column.Name, column.Width)
+		}
+	}
+	return nil
+}
+
+// ParseWidths lit des colonnes à largeur fixe "nom:largeur", séparées par
+// des virgules (ex: id:4,nom:20,ville:12)
+func ParseWidths(spec string) ([]FixedColumn, error) {
+	var columns []FixedColumn
+	for _, part := range strings.Split(spec, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, width, ok := strings.Cut(part, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(width))
+		if !ok || err != nil || n <= 0 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("colonne à largeur fixe invalide: %q (nom:largeur attendu)", part)
+		}
+		columns = append(columns, FixedColumn{Name: strings.TrimSpace(name), Width: n})
+	}
+	return columns, nil
+}
+
+// ParseNamespace lit une déclaration d'espace de noms "prefixe=uri", ou
+// "=uri" pour l'espace de noms par défaut
+func ParseNamespace(spec string) (string, string, error) {
+	prefix, uri, ok := strings.Cut(spec, "=")
+	if !ok || strings.TrimSpace(uri) == "" {
+		return "", "", fmt.Errorf("espace de noms invalide: %q (prefixe=uri attendu)", spec)
+	}
+	return strings.TrimSpace(prefix), strings.TrimSpace(uri), nil
+}
+
+// Validate vérifie la cohérence des options de lecture et d'écriture
+func (o ConvertOptions) Validate() error {
+	if err := o.CSV.validate(); err != nil {
+		return err
+	}
+	if err := o.writeOptions(o.OutputFormat).validate(); err != nil {
+		return err
+	}
+	if strings.Trim(o.JSON.Indent, " \t") != "" {
+		return fmt.Errorf("indentation JSON invalide: %q", o.JSON.Indent)
+	}
+	for _, name := range []string{o.Encoding, o.OutputEncoding} {
+		if name == "" {
+			continue
+		}
+		if _, err := lookupEncoding(name); err != nil {
+			return err
+		}
+	}
+	if err := o.Flatten.validate(); err != nil {
+		return err
+	}
+	if err := o.Types.validate(); err != nil {
+		return err
+	}
+	if err := o.XML.validate(); err != nil {
+		return err
+	}
+	if err := o.TXT.validate(); err != nil {
+		return err
+	}
+	if o.HTML.Table < 0 {
+		return fmt.Errorf("numéro de tableau HTML invalide: %d", o.HTML.Table)
+	}
+	return nil
+}
+
+// ParseChar lit un caractère d'option: un caractère seul, "\t" ou "tab"
+func ParseChar(value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case `\t`, "tab":
+		return '\t', nil
+	case "space":
+		return ' ', nil
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("un seul caractère est attendu: %q", value)
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
+}
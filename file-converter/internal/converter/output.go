@@ -0,0 +1,57 @@
+// internal/converter/output.go
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SameFile indique si deux chemins désignent le même fichier existant
+func SameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// WriteFile écrit un fichier de sortie au travers d'un fichier temporaire du
+// même dossier, renommé sur path seulement si write réussit: un fichier
+// existant n'est jamais tronqué ni supprimé par une conversion qui échoue.
+// input, s'il est donné, est le fichier lu par la conversion; path ne doit
+// pas le désigner. Les erreurs de write sont retournées telles quelles.
+func WriteFile(path, input string, write func(w io.Writer) error) error {
+	if input != "" && SameFile(path, input) {
+		return fmt.Errorf("le fichier de sortie est le fichier d'entrée: %s", path)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("erreur lors de la sauvegarde du fichier: %v", err)
+	}
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erreur lors de la sauvegarde du fichier: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erreur lors de la sauvegarde du fichier: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("erreur lors de la sauvegarde du fichier: %v", err)
+	}
+	return nil
+}
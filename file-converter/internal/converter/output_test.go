@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		sameAs  bool // La sortie est le fichier d'entrée
+		failing bool // La conversion échoue
+		want    string
+		wantErr bool
+	}{
+		{name: "remplace la sortie", want: "nouveau"},
+		{name: "conversion en échec", failing: true, want: "ancien", wantErr: true},
+		{name: "sortie identique à l'entrée", sameAs: true, want: "ancien", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "entree.json")
+			output := filepath.Join(dir, "sortie.csv")
+			if tt.sameAs {
+				output = input
+			}
+			for _, path := range []string{input, output} {
+				if err := os.WriteFile(path, []byte("ancien"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := WriteFile(output, input, func(w io.Writer) error {
+				if tt.failing {
+					io.WriteString(w, "partiel")
+					return fmt.Errorf("échec")
+				}
+				_, err := io.WriteString(w, "nouveau")
+				return err
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteFile() erreur = %v, attendu %v", err, tt.wantErr)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("contenu = %q, attendu %q", data, tt.want)
+			}
+			files := 2
+			if tt.sameAs {
+				files = 1
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != files {
+				t.Errorf("fichier temporaire laissé: %v", entries)
+			}
+		})
+	}
+}
+
+func TestConvertFileSamePath(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "data.json")
+	content := `[{"id":1},{"id":2}]`
+	if err := os.WriteFile(input, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ConvertFile(&TextConverter{}, input, "json"); err == nil {
+		t.Fatal("ConvertFile() vers le fichier d'entrée: erreur attendue")
+	}
+	data, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatalf("fichier d'entrée supprimé: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("fichier d'entrée modifié: %q", data)
+	}
+}
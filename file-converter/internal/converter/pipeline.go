@@ -0,0 +1,188 @@
+// internal/converter/pipeline.go
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// PipelineSeparator sépare les étapes d'un pipeline ("csv+gzip")
+const PipelineSeparator = "+"
+
+// PipelineStep est une étape d'un pipeline
+type PipelineStep struct {
+	Converter Converter
+	Format    Format
+	Input     string // Format lu par l'étape, détecté d'après le contenu si vide
+}
+
+// Pipeline enchaîne plusieurs conversions. Les résultats intermédiaires
+// transitent d'une étape à l'autre par des io.Pipe, sans passer par le disque.
+type Pipeline struct {
+	Steps []PipelineStep
+}
+
+// ParsePipeline construit un pipeline à partir d'une spécification "csv+gzip"
+func (r *Registry) ParsePipeline(spec string) (*Pipeline, error) {
+	names := strings.Split(spec, PipelineSeparator)
+	p := &Pipeline{}
+	prev := ""
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("pipeline invalide: %s", spec)
+		}
+		conv, f, err := r.ResolveFrom(prev, name)
+		if err != nil {
+			return nil, err
+		}
+		p.Steps = append(p.Steps, PipelineStep{Converter: conv, Format: f, Input: prev})
+
+		// Après une décompression, le format du contenu n'est plus connu
+		prev = f.Name
+		if f.Unwraps != "" {
+			prev = ""
+		}
+	}
+	return p, nil
+}
+
+// ParsePipeline construit un pipeline à partir du registre par défaut
+func ParsePipeline(spec string) (*Pipeline, error) {
+	return DefaultRegistry.ParsePipeline(spec)
+}
+
+// Output retourne le format produit par la dernière étape
+func (p *Pipeline) Output() Format {
+	if len(p.Steps) == 0 {
+		return Format{}
+	}
+	return p.Steps[len(p.Steps)-1].Format
+}
+
+// String retourne la spécification du pipeline ("csv+gzip")
+func (p *Pipeline) String() string {
+	names := make([]string, len(p.Steps))
+	for i, step := range p.Steps {
+		names[i] = step.Format.Name
+	}
+	return strings.Join(names, PipelineSeparator)
+}
+
+// OutputFileName applique successivement le nommage de chaque étape
+// ("data.json" avec "csv+gzip" → "data.csv.gz")
+func (p *Pipeline) OutputFileName(inputPath string) string {
+	name := inputPath
+	for _, step := range p.Steps {
+		name = OutputFileName(name, step.Format)
+	}
+	return name
+}
+
+// GetSupportedFormats retourne le format produit par le pipeline
+func (p *Pipeline) GetSupportedFormats() []SupportedFormat {
+	out := p.Output()
+	return []SupportedFormat{{Name: out.Label, Extension: out.Extension(), ContentType: out.ContentType()}}
+}
+
+// Convert implémente l'interface Converter
+func (p *Pipeline) Convert(input []byte, outputFormat string) ([]byte, error) {
+	return convertBytes(p, input, outputFormat)
+}
+
+// ConvertStream exécute toutes les étapes en parallèle, chacune lisant
+// la sortie de la précédente. La première erreur rencontrée interrompt le pipeline.
+func (p *Pipeline) ConvertStream(ctx context.Context, r io.Reader, w io.Writer, opts ConvertOptions) error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("pipeline vide")
+	}
+	if opts.OutputFormat != "" && opts.OutputFormat != p.Output().Name && opts.OutputFormat != p.String() {
+		return fmt.Errorf("le pipeline %s ne produit pas le format %s", p, opts.OutputFormat)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(i int, err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = fmt.Errorf("étape %d (%s): %v", i+1, p.Steps[i].Format.Name, err)
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	steps := p.stepOptions(opts)
+	src := r
+	last := len(p.Steps) - 1
+	for i, step := range p.Steps[:last] {
+		pr, pw := io.Pipe()
+		wg.Add(1)
+		go func(i int, step PipelineStep, in io.Reader) {
+			defer wg.Done()
+			err := runStep(ctx, step, in, pw, steps[i])
+			if err != nil {
+				fail(i, err)
+			}
+			pw.CloseWithError(err)
+		}(i, step, src)
+		src = pr
+	}
+
+	if err := runStep(ctx, p.Steps[last], src, w, steps[last]); err != nil {
+		fail(last, err)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// stepOptions retourne les options de chaque étape: l'encodage d'entrée ne
+// concerne que la première étape texte, celui de sortie la dernière; entre
+// les deux, le texte circule en UTF-8
+func (p *Pipeline) stepOptions(opts ConvertOptions) []ConvertOptions {
+	steps := make([]ConvertOptions, len(p.Steps))
+	first, last := -1, -1
+	for i, step := range p.Steps {
+		steps[i] = opts
+		if _, ok := step.Converter.(*TextConverter); ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	for i := range steps {
+		if i != first {
+			steps[i].Encoding = ""
+		}
+		if i != last {
+			steps[i].OutputEncoding = ""
+		}
+	}
+	return steps
+}
+
+// runStep exécute une étape puis libère son entrée: en cas d'erreur l'étape
+// précédente est débloquée, en cas de succès le reste du flux est consommé
+func runStep(ctx context.Context, step PipelineStep, in io.Reader, out io.Writer, opts ConvertOptions) error {
+	opts.OutputFormat = step.Format.Name
+	opts.InputFormat = step.Input
+	err := step.Converter.ConvertStream(ctx, in, out, opts)
+
+	if pr, ok := in.(*io.PipeReader); ok {
+		if err != nil {
+			pr.CloseWithError(err)
+		} else {
+			io.Copy(io.Discard, pr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParsePipeline(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string // Pipeline attendu, "" pour une erreur
+		outName string // Nom du fichier produit pour "dir/data.json"
+	}{
+		{spec: "csv", want: "csv", outName: "data.csv"},
+		{spec: "csv+gzip", want: "csv+gzip", outName: "data.csv.gz"},
+		{spec: "json+csv+gzip", want: "json+csv+gzip", outName: "data.csv.gz"},
+		{spec: "XML+gz", want: "xml+gzip", outName: "data.xml.gz"},
+		{spec: "csv++gzip"},
+		{spec: "csv+"},
+		{spec: "inconnu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := ParsePipeline(tt.spec)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("ParsePipeline(%s) = %s, erreur attendue", tt.spec, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.String() != tt.want {
+				t.Errorf("String() = %s, attendu %s", p, tt.want)
+			}
+			if got := p.OutputFileName("dir/data.json"); got != tt.outName {
+				t.Errorf("OutputFileName() = %s, attendu %s", got, tt.outName)
+			}
+		})
+	}
+}
+
+func TestPipelineConvertStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		input   string
+		want    string // Sortie décompressée attendue
+		wantErr string // Début du message d'erreur attendu
+	}{
+		{name: "conversion puis compression", spec: "csv+gzip", input: `[{"id":1},{"id":2}]`,
+			want: "id\n1\n2\n"},
+		{name: "deux conversions", spec: "xml+json+csv+gzip", input: `[{"id":1}]`, want: "id\n1\n"},
+		{name: "erreur de la première étape", spec: "csv+gzip", input: `[{"id":1}`, wantErr: "étape 1 (csv)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePipeline(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			err = p.ConvertStream(context.Background(), strings.NewReader(tt.input), &out, ConvertOptions{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %s...", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			zr, err := gzip.NewReader(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("sortie = %q, attendu %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineStepOptions(t *testing.T) {
+	tests := []struct {
+		spec string
+		in   []string // Encodage d'entrée de chaque étape
+		out  []string // Encodage de sortie de chaque étape
+	}{
+		{spec: "csv", in: []string{"latin1"}, out: []string{"utf-16"}},
+		{spec: "csv+gzip", in: []string{"latin1", ""}, out: []string{"utf-16", ""}},
+		{spec: "gunzip+json+csv+gzip", in: []string{"", "latin1", "", ""}, out: []string{"", "", "utf-16", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := ParsePipeline(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			steps := p.stepOptions(ConvertOptions{Encoding: "latin1", OutputEncoding: "utf-16"})
+			for i, opts := range steps {
+				if opts.Encoding != tt.in[i] || opts.OutputEncoding != tt.out[i] {
+					t.Errorf("étape %d (%s): encodages %q → %q, attendu %q → %q",
+						i+1, p.Steps[i].Format.Name, opts.Encoding, opts.OutputEncoding, tt.in[i], tt.out[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// internal/converter/record.go
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Record est l'enregistrement échangé entre les lecteurs et les écrivains
+// de texte. Les champs gardent l'ordre de la source et les valeurs leur type:
+// nil, bool, json.Number, string, Date, *Record (objet imbriqué) ou
+// []interface{} (liste de ces mêmes valeurs).
+type Record struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewRecord crée un enregistrement vide
+func NewRecord() *Record {
+	return &Record{values: make(map[string]interface{})}
+}
+
+// Set ajoute ou remplace un champ; un nouveau champ est placé en dernier
+func (r *Record) Set(key string, value interface{}) {
+	if r.values == nil {
+		r.values = make(map[string]interface{})
+	}
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// Get retourne la valeur d'un champ
+func (r *Record) Get(key string) (interface{}, bool) {
+	value, ok := r.values[key]
+	return value, ok
+}
+
+// Keys retourne les champs dans l'ordre de la source
+func (r *Record) Keys() []string {
+	return r.keys
+}
+
+// Len retourne le nombre de champs
+func (r *Record) Len() int {
+	return len(r.keys)
+}
+
+// project ne garde que les colonnes demandées, dans leur ordre
+func (r *Record) project(columns []string) *Record {
+	out := NewRecord()
+	for _, column := range columns {
+		if value, ok := r.values[column]; ok {
+			out.Set(column, value)
+		}
+	}
+	return out
+}
+
+// MarshalJSON encode l'enregistrement en objet JSON dans l'ordre des champs
+func (r *Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON décode un objet JSON en gardant l'ordre des clés, y compris
+// dans les objets imbriqués. Les nombres sont conservés tels quels (json.Number).
+func (r *Record) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case *Record:
+		*r = *v
+	case nil:
+		*r = Record{}
+	default:
+		return fmt.Errorf("un objet JSON est attendu, reçu %s", jsonKind(value))
+	}
+	return nil
+}
+
+// decodeJSONValue lit une valeur JSON complète vers le modèle de Record
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONToken(decoder, tok)
+}
+
+// decodeJSONToken termine la lecture d'une valeur dont tok est le premier jeton.
+// Une fin de contenu au milieu de la valeur est une erreur, pas une fin normale.
+func decodeJSONToken(decoder *json.Decoder, tok json.Token) (interface{}, error) {
+	value, err := decodeJSONRest(decoder, tok)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return value, err
+}
+
+func decodeJSONRest(decoder *json.Decoder, tok json.Token) (interface{}, error) {
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		record := NewRecord()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			record.Set(key.(string), value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return record, nil
+	default:
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+}
+
+// jsonKind nomme le type d'une valeur du modèle: un type JSON, ou "date"
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64, int, int64:
+		return "number"
+	case string:
+		return "string"
+	case Date:
+		return "date"
+	case *Record:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// FormatValue retourne le texte d'une valeur pour une cellule ou une ligne de
+// texte: vide pour nil, JSON compact pour les objets et les listes
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *Record, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// columnName nomme une colonne positionnelle: col1, col2...
+func columnName(i int) string {
+	return fmt.Sprintf("col%d", i+1)
+}
+
+// Table regroupe des enregistrements et l'union de leurs colonnes,
+// dans l'ordre d'apparition
+type Table struct {
+	Columns []string
+	Records []*Record
+
+	seen map[string]bool
+}
+
+// Append ajoute un enregistrement et complète les colonnes
+func (t *Table) Append(r *Record) {
+	if t.seen == nil {
+		t.seen = make(map[string]bool)
+		for _, column := range t.Columns {
+			t.seen[column] = true
+		}
+	}
+	for _, key := range r.Keys() {
+		if !t.seen[key] {
+			t.seen[key] = true
+			t.Columns = append(t.Columns, key)
+		}
+	}
+	t.Records = append(t.Records, r)
+}
+
+// Row retourne les cellules d'un enregistrement, dans l'ordre des colonnes
+func (t *Table) Row(r *Record) []string {
+	row := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		if value, ok := r.Get(column); ok {
+			row[i] = FormatValue(value)
+		}
+	}
+	return row
+}
+
+// xmlType est l'attribut "type" d'un champ XML; absent pour une chaîne
+func xmlType(value interface{}) string {
+	kind := jsonKind(value)
+	if kind == "string" {
+		return ""
+	}
+	return kind
+}
+
+// parseXMLValue convertit le texte d'un champ XML selon son attribut "type"
+func parseXMLValue(kind, text string) (interface{}, error) {
+	switch kind {
+	case "", "string":
+		return text, nil
+	case "null":
+		return nil, nil
+	case "date":
+		if date, ok := parseDate(strings.TrimSpace(text)); ok {
+			return date, nil
+		}
+	case "boolean":
+		switch strings.TrimSpace(text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case "number":
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		var value interface{}
+		if decoder.Decode(&value) == nil && !decoder.More() {
+			if number, ok := value.(json.Number); ok {
+				return number, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("type de champ inconnu: %s", kind)
+	}
+	return nil, fmt.Errorf("valeur %q invalide pour le type %s", text, kind)
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRecordOrder(t *testing.T) {
+	r := NewRecord()
+	r.Set("b", 1)
+	r.Set("a", 2)
+	r.Set("b", 3)
+	if got, want := r.Keys(), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, attendu %v", got, want)
+	}
+	if value, _ := r.Get("b"); value != 3 {
+		t.Errorf("Get(b) = %v, attendu 3", value)
+	}
+	if _, ok := r.Get("c"); ok {
+		t.Error("Get(c) trouvé, attendu absent")
+	}
+}
+
+func TestRecordJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"z":1,"a":{"y":true,"b":null},"m":[1.50,"x",{"k":"v","c":2}]}`, `{"z":1,"a":{"y":true,"b":null},"m":[1.50,"x",{"k":"v","c":2}]}`},
+		{`{}`, `{}`},
+		{`null`, `{}`},
+		{` { "b" : 1 , "a" : 2 } `, `{"b":1,"a":2}`},
+	}
+	for _, tt := range tests {
+		var r Record
+		if err := json.Unmarshal([]byte(tt.input), &r); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.input, err)
+		}
+		got, err := json.Marshal(&r)
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", tt.input, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s relu en %s, attendu %s", tt.input, got, tt.want)
+		}
+	}
+
+	var r Record
+	if err := json.Unmarshal([]byte(`[1]`), &r); err == nil {
+		t.Error("Unmarshal([1]) sans erreur, attendu une erreur")
+	}
+}
+
+func TestTable(t *testing.T) {
+	var table Table
+	for _, input := range []string{`{"b":1,"a":{"x":1}}`, `{"c":null,"a":"y"}`} {
+		r := NewRecord()
+		if err := json.Unmarshal([]byte(input), r); err != nil {
+			t.Fatal(err)
+		}
+		table.Append(r)
+	}
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("Columns = %v, attendu %v", table.Columns, want)
+	}
+	rows := [][]string{table.Row(table.Records[0]), table.Row(table.Records[1])}
+	if want := [][]string{{"1", `{"x":1}`, ""}, {"", "y", ""}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("lignes = %q, attendu %q", rows, want)
+	}
+}
+
+func TestRecordXMLRoundTrip(t *testing.T) {
+	input := `[{"z":1,"a":{"y":true,"b":null},"m":[1.5,"x"],"s":"texte"}]`
+	xmlText, err := convertText(input, ConvertOptions{OutputFormat: "xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := convertText(xmlText, ConvertOptions{OutputFormat: "json", JSON: JSONOptions{Compact: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != input {
+		t.Errorf("relu en %s, attendu %s\nXML:\n%s", got, input, xmlText)
+	}
+}
@@ -0,0 +1,328 @@
+// internal/converter/registry.go
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Catégories de formats
+const (
+	CategoryText        = "Texte"
+	CategoryImage       = "Image"
+	CategoryCompression = "Compression"
+)
+
+// Categories liste les catégories dans leur ordre d'affichage
+var Categories = []string{CategoryText, CategoryImage, CategoryCompression}
+
+// Format décrit un format connu du registre
+type Format struct {
+	Name       string   // Identifiant canonique ("json", "gzip", ...)
+	Label      string   // Nom affiché ("JSON", "GZIP", ...)
+	Category   string   // Famille du format (CategoryText, ...)
+	Aliases    []string // Autres noms acceptés ("gz" pour "gzip", "jpg" pour "jpeg")
+	MIMETypes  []string // Le premier sert de Content-Type
+	Extensions []string // Sans le point, la première est l'extension par défaut
+	Unwraps    string   // Pour une décompression: format conteneur retiré ("gzip" pour "gunzip")
+}
+
+// ContentType retourne le Content-Type principal du format
+func (f Format) ContentType() string {
+	if len(f.MIMETypes) == 0 {
+		return "application/octet-stream"
+	}
+	return f.MIMETypes[0]
+}
+
+// Extension retourne l'extension par défaut du format (sans le point)
+func (f Format) Extension() string {
+	if len(f.Extensions) == 0 {
+		return ""
+	}
+	return f.Extensions[0]
+}
+
+// Registration associe un convertisseur aux formats qu'il traite
+type Registration struct {
+	Name    string           // Nom du convertisseur ("text", "image", ...)
+	Inputs  []string         // Formats d'entrée acceptés, "*" pour n'importe lequel
+	Outputs []string         // Formats de sortie produits
+	New     func() Converter // Constructeur du convertisseur
+}
+
+// Accepts indique si la registration accepte le format d'entrée donné
+func (reg Registration) Accepts(input string) bool {
+	return containsFormat(reg.Inputs, input) || containsFormat(reg.Inputs, "*")
+}
+
+// Produces indique si la registration produit le format de sortie donné
+func (reg Registration) Produces(output string) bool {
+	return containsFormat(reg.Outputs, output)
+}
+
+// Registry référence les formats et les convertisseurs disponibles
+type Registry struct {
+	mu            sync.RWMutex
+	formats       []*Format
+	byName        map[string]*Format // nom canonique, alias et extensions
+	byMIME        map[string]*Format
+	registrations []Registration
+}
+
+// NewRegistry crée un registre vide
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]*Format),
+		byMIME: make(map[string]*Format),
+	}
+}
+
+// DefaultRegistry est le registre utilisé par la CLI et l'API
+var DefaultRegistry = NewRegistry()
+
+// RegisterFormat ajoute un format au registre
+func (r *Registry) RegisterFormat(f Format) error {
+	if f.Name == "" {
+		return fmt.Errorf("format sans nom")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := append([]string{f.Name}, f.Aliases...)
+	keys = append(keys, f.Extensions...)
+	for _, key := range keys {
+		if existing, ok := r.byName[normalizeFormat(key)]; ok && existing.Name != f.Name {
+			return fmt.Errorf("le nom %s est déjà utilisé par le format %s", key, existing.Name)
+		}
+	}
+	if _, ok := r.byName[normalizeFormat(f.Name)]; ok {
+		return fmt.Errorf("format déjà enregistré: %s", f.Name)
+	}
+
+	format := f
+	r.formats = append(r.formats, &format)
+	for _, key := range keys {
+		r.byName[normalizeFormat(key)] = &format
+	}
+	for _, mime := range f.MIMETypes {
+		if _, ok := r.byMIME[strings.ToLower(mime)]; !ok {
+			r.byMIME[strings.ToLower(mime)] = &format
+		}
+	}
+	return nil
+}
+
+// RegisterConverter ajoute un convertisseur au registre.
+// Les formats de sortie doivent avoir été enregistrés au préalable.
+func (r *Registry) RegisterConverter(reg Registration) error {
+	if reg.New == nil {
+		return fmt.Errorf("convertisseur %s sans constructeur", reg.Name)
+	}
+
+	reg.Inputs = append([]string(nil), reg.Inputs...)
+	reg.Outputs = append([]string(nil), reg.Outputs...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, name := range reg.Outputs {
+		f, ok := r.byName[normalizeFormat(name)]
+		if !ok {
+			return fmt.Errorf("convertisseur %s: format de sortie inconnu: %s", reg.Name, name)
+		}
+		reg.Outputs[i] = f.Name
+	}
+	for i, name := range reg.Inputs {
+		if name == "*" {
+			continue
+		}
+		f, ok := r.byName[normalizeFormat(name)]
+		if !ok {
+			return fmt.Errorf("convertisseur %s: format d'entrée inconnu: %s", reg.Name, name)
+		}
+		reg.Inputs[i] = f.Name
+	}
+
+	r.registrations = append(r.registrations, reg)
+	return nil
+}
+
+// Lookup retrouve un format par son nom, un alias ou une extension (avec ou sans point)
+func (r *Registry) Lookup(name string) (Format, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.byName[normalizeFormat(name)]
+	if !ok {
+		return Format{}, false
+	}
+	return *f, true
+}
+
+// LookupMIME retrouve un format par son type MIME (les paramètres sont ignorés)
+func (r *Registry) LookupMIME(mime string) (Format, bool) {
+	if i := strings.Index(mime, ";"); i >= 0 {
+		mime = mime[:i]
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.byMIME[strings.ToLower(strings.TrimSpace(mime))]
+	if !ok {
+		return Format{}, false
+	}
+	return *f, true
+}
+
+// LookupFile retrouve le format d'un fichier à partir de son extension
+func (r *Registry) LookupFile(filename string) (Format, bool) {
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		return Format{}, false
+	}
+	return r.Lookup(ext)
+}
+
+// Formats retourne les formats enregistrés dans l'ordre d'enregistrement
+func (r *Registry) Formats() []Format {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	formats := make([]Format, len(r.formats))
+	for i, f := range r.formats {
+		formats[i] = *f
+	}
+	return formats
+}
+
+// Registrations retourne les convertisseurs enregistrés
+func (r *Registry) Registrations() []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Registration(nil), r.registrations...)
+}
+
+// Convertible indique si au moins un convertisseur lit ou produit le format
+func (r *Registry) Convertible(name string) bool {
+	f, ok := r.Lookup(name)
+	if !ok {
+		return false
+	}
+	for _, reg := range r.Registrations() {
+		if reg.Produces(f.Name) || containsFormat(reg.Inputs, f.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve retourne le convertisseur capable de produire le format demandé
+// ainsi que le format canonique à passer à Convert. Une spécification à
+// plusieurs étapes ("csv+gzip") retourne un *Pipeline et le format final.
+func (r *Registry) Resolve(output string) (Converter, Format, error) {
+	if strings.Contains(output, PipelineSeparator) {
+		p, err := r.ParsePipeline(output)
+		if err != nil {
+			return nil, Format{}, err
+		}
+		return p, p.Output(), nil
+	}
+	return r.ResolveFrom("", output)
+}
+
+// ResolveFrom retourne le convertisseur qui produit output à partir de input.
+// Un input vide accepte n'importe quel convertisseur produisant output.
+func (r *Registry) ResolveFrom(input, output string) (Converter, Format, error) {
+	f, ok := r.Lookup(output)
+	if !ok {
+		return nil, Format{}, fmt.Errorf("format non supporté: %s", output)
+	}
+	if input != "" {
+		in, ok := r.Lookup(input)
+		if !ok {
+			return nil, Format{}, fmt.Errorf("format non supporté: %s", input)
+		}
+		input = in.Name
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, reg := range r.registrations {
+		if reg.Produces(f.Name) && (input == "" || reg.Accepts(input)) {
+			return reg.New(), f, nil
+		}
+	}
+	if input != "" {
+		return nil, Format{}, fmt.Errorf("aucun convertisseur de %s vers %s", input, f.Name)
+	}
+	return nil, Format{}, fmt.Errorf("aucun convertisseur pour le format: %s", f.Name)
+}
+
+// RegisterFormat ajoute un format au registre par défaut et panique en cas d'erreur
+func RegisterFormat(f Format) {
+	if err := DefaultRegistry.RegisterFormat(f); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterConverter ajoute un convertisseur au registre par défaut et panique en cas d'erreur
+func RegisterConverter(reg Registration) {
+	if err := DefaultRegistry.RegisterConverter(reg); err != nil {
+		panic(err)
+	}
+}
+
+// LookupFormat retrouve un format dans le registre par défaut
+func LookupFormat(name string) (Format, bool) {
+	return DefaultRegistry.Lookup(name)
+}
+
+// Resolve retourne le convertisseur du registre par défaut pour le format demandé
+func Resolve(output string) (Converter, Format, error) {
+	return DefaultRegistry.Resolve(output)
+}
+
+func normalizeFormat(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))
+}
+
+func containsFormat(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// OutputFileName calcule le nom du fichier produit à partir du fichier d'entrée.
+// Une compression ajoute son extension ("data.csv" → "data.csv.gz"), une
+// décompression retire celle du conteneur ("data.csv.gz" → "data.csv") et
+// les autres formats remplacent l'extension ("data.csv" → "data.json").
+func OutputFileName(inputPath string, f Format) string {
+	base := filepath.Base(inputPath)
+	ext := filepath.Ext(base)
+
+	if f.Unwraps != "" {
+		if container, ok := LookupFormat(ext); ok && ext != "" && container.Name == f.Unwraps {
+			return strings.TrimSuffix(base, ext)
+		}
+		return base + ".out"
+	}
+
+	outExt := f.Extension()
+	if outExt == "" {
+		outExt = f.Name
+	}
+	if f.Category == CategoryCompression {
+		return base + "." + outExt
+	}
+	return strings.TrimSuffix(base, ext) + "." + outExt
+}
@@ -0,0 +1,28 @@
+package converter
+
+import "testing"
+
+// Formats reconnus par la détection sans convertisseur, signalés comme tels
+// par la commande list
+var detectionOnly = map[string]bool{"tar": true, "bmp": true, "webp": true}
+
+func TestRegisteredFormatsAreConvertible(t *testing.T) {
+	for _, f := range DefaultRegistry.Formats() {
+		if got := DefaultRegistry.Convertible(f.Name); got != !detectionOnly[f.Name] {
+			t.Errorf("format %s: convertible = %v, attendu %v", f.Name, got, !detectionOnly[f.Name])
+		}
+	}
+}
+
+func TestRegistrationFormatsAreRegistered(t *testing.T) {
+	for _, reg := range DefaultRegistry.Registrations() {
+		for _, name := range append(append([]string{}, reg.Inputs...), reg.Outputs...) {
+			if name == "*" {
+				continue
+			}
+			if _, ok := DefaultRegistry.Lookup(name); !ok {
+				t.Errorf("convertisseur %s: format %s non enregistré", reg.Name, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// internal/converter/route.go
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Nombre maximal d'étapes explorées lors de la recherche d'un chemin
+const maxRouteSteps = 6
+
+// Edge est une conversion directe entre deux formats du registre
+type Edge struct {
+	From      string // Format d'entrée, "*" pour n'importe lequel
+	To        string // Format (ou opération) produit
+	Converter string // Nom du convertisseur
+}
+
+// Graph retourne toutes les conversions directes connues du registre
+func (r *Registry) Graph() []Edge {
+	var edges []Edge
+	for _, reg := range r.Registrations() {
+		for _, in := range reg.Inputs {
+			for _, out := range reg.Outputs {
+				edges = append(edges, Edge{From: in, To: out, Converter: reg.Name})
+			}
+		}
+	}
+	return edges
+}
+
+// LayersFromFileName déduit les couches de formats d'un nom de fichier,
+// de la plus externe à la plus interne: "data.csv.gz" → ["gzip", "csv"].
+// Seules les compressions peuvent envelopper un autre format.
+func (r *Registry) LayersFromFileName(name string) []string {
+	var layers []string
+	base := filepath.Base(name)
+	for {
+		ext := filepath.Ext(base)
+		if ext == "" {
+			break
+		}
+		f, ok := r.Lookup(ext)
+		if !ok || f.Unwraps != "" {
+			break
+		}
+		layers = append(layers, f.Name)
+		if f.Category != CategoryCompression {
+			break
+		}
+		base = strings.TrimSuffix(base, ext)
+	}
+	return layers
+}
+
+// unknownLayer représente un contenu dont le format n'est pas connu
+// (par exemple après avoir décompressé "data.gz"): tout convertisseur l'accepte
+const unknownLayer = "?"
+
+// FindRoute calcule le plus court enchaînement de conversions menant des
+// couches d'entrée (voir LayersFromFileName) au format demandé, par exemple
+// ["gzip", "csv"] vers "xml" donne "gunzip+xml". Sans couche d'entrée connue,
+// le convertisseur direct du format de sortie est retourné.
+func (r *Registry) FindRoute(layers []string, output string) (*Pipeline, error) {
+	p, _, err := r.findRoute(layers, output)
+	return p, err
+}
+
+// PlanRoute calcule un chemin passant par chacune des étapes d'une
+// spécification "json+zip": les étapes intermédiaires manquantes, comme une
+// décompression préalable, sont ajoutées automatiquement.
+func (r *Registry) PlanRoute(layers []string, spec string) (*Pipeline, error) {
+	plan := &Pipeline{}
+	for _, name := range strings.Split(spec, PipelineSeparator) {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("pipeline invalide: %s", spec)
+		}
+		p, next, err := r.findRoute(layers, name)
+		if err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, p.Steps...)
+		layers = next
+	}
+	return plan, nil
+}
+
+func (r *Registry) findRoute(layers []string, output string) (*Pipeline, []string, error) {
+	target, ok := r.Lookup(output)
+	if !ok {
+		return nil, nil, fmt.Errorf("format non supporté: %s", output)
+	}
+
+	// Un format reconnu mais qu'aucun convertisseur ne lit est traité comme
+	// inconnu: les convertisseurs analysent eux-mêmes leur entrée
+	layers = append([]string(nil), layers...)
+	for i, layer := range layers {
+		if layer != unknownLayer && !r.Convertible(layer) {
+			layers[i] = unknownLayer
+		}
+	}
+
+	// Entrée inconnue, opération explicite ou format déjà atteint: conversion directe
+	if len(layers) == 0 || target.Unwraps != "" || (len(layers) == 1 && layers[0] == target.Name) {
+		input := ""
+		if len(layers) > 0 && layers[0] != unknownLayer {
+			input = layers[0]
+		}
+		conv, f, err := r.ResolveFrom(input, target.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		next := []string{f.Name}
+		switch {
+		case f.Unwraps != "" && len(layers) > 1:
+			next = layers[1:]
+		case f.Unwraps != "":
+			next = []string{unknownLayer}
+		case f.Category == CategoryCompression:
+			next = append(next, layers...)
+		}
+		return &Pipeline{Steps: []PipelineStep{{Converter: conv, Format: f, Input: input}}}, next, nil
+	}
+
+	type hop struct {
+		reg    Registration
+		format Format
+		input  string
+	}
+	type state struct {
+		layers []string
+		path   []hop
+	}
+
+	regs := r.Registrations()
+	maxDepth := len(layers) + 1
+	visited := map[string]bool{strings.Join(layers, "/"): true}
+	queue := []state{{layers: layers}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if len(current.path) >= maxRouteSteps {
+			continue
+		}
+
+		top := current.layers[0]
+		for _, reg := range regs {
+			for _, out := range reg.Outputs {
+				f, _ := r.Lookup(out)
+
+				var next []string
+				switch {
+				case f.Unwraps != "":
+					// Décompression: retire la couche externe si elle correspond
+					if f.Unwraps != top {
+						continue
+					}
+					next = current.layers[1:]
+					if len(next) == 0 {
+						next = []string{unknownLayer}
+					}
+				case top != unknownLayer && !reg.Accepts(top):
+					continue
+				case f.Category == CategoryCompression:
+					// Compression: ajoute une couche autour du contenu
+					if len(current.layers) >= maxDepth {
+						continue
+					}
+					next = append([]string{f.Name}, current.layers...)
+				default:
+					if f.Name == top {
+						continue
+					}
+					next = append([]string{f.Name}, current.layers[1:]...)
+				}
+
+				key := strings.Join(next, "/")
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+
+				input := top
+				if input == unknownLayer {
+					input = ""
+				}
+				path := append(append([]hop(nil), current.path...), hop{reg: reg, format: f, input: input})
+				if next[0] == target.Name && (len(next) == 1 || target.Category == CategoryCompression) {
+					p := &Pipeline{}
+					for _, h := range path {
+						p.Steps = append(p.Steps, PipelineStep{Converter: h.reg.New(), Format: h.format, Input: h.input})
+					}
+					return p, next, nil
+				}
+				queue = append(queue, state{layers: next, path: path})
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("aucun chemin de conversion de %s vers %s", r.DescribeLayers(layers), target.Name)
+}
+
+// DescribeLayers affiche des couches sous forme d'extensions ("csv.gz")
+func (r *Registry) DescribeLayers(layers []string) string {
+	if len(layers) == 0 {
+		return "inconnu"
+	}
+	parts := make([]string, 0, len(layers))
+	for i := len(layers) - 1; i >= 0; i-- {
+		name := layers[i]
+		if f, ok := r.Lookup(name); ok && f.Extension() != "" {
+			name = f.Extension()
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, ".")
+}
+
+// ParseLayers lit des couches écrites comme des extensions ("csv.gz"),
+// l'inverse de DescribeLayers: "csv.gz" → ["gzip", "csv"]
+func (r *Registry) ParseLayers(spec string) ([]string, error) {
+	parts := strings.Split(normalizeFormat(spec), ".")
+	layers := make([]string, 0, len(parts))
+	for i := len(parts) - 1; i >= 0; i-- {
+		f, ok := r.Lookup(parts[i])
+		if !ok || f.Unwraps != "" {
+			return nil, fmt.Errorf("format d'entrée non supporté: %s", parts[i])
+		}
+		// Seule une compression peut envelopper un autre format
+		if len(layers) > 0 {
+			if outer, _ := r.Lookup(layers[len(layers)-1]); outer.Category != CategoryCompression {
+				return nil, fmt.Errorf("format d'entrée invalide: %s", spec)
+			}
+		}
+		layers = append(layers, f.Name)
+	}
+	return layers, nil
+}
+
+// ParseLayers lit des couches avec le registre par défaut
+func ParseLayers(spec string) ([]string, error) {
+	return DefaultRegistry.ParseLayers(spec)
+}
+
+// FindRoute calcule un chemin de conversion avec le registre par défaut
+func FindRoute(layers []string, output string) (*Pipeline, error) {
+	return DefaultRegistry.FindRoute(layers, output)
+}
+
+// PlanRoute calcule un chemin par étapes avec le registre par défaut
+func PlanRoute(layers []string, spec string) (*Pipeline, error) {
+	return DefaultRegistry.PlanRoute(layers, spec)
+}
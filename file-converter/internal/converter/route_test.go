@@ -0,0 +1,116 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindRoute(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers []string
+		output string
+		want   string // Pipeline attendu, "" pour une erreur
+	}{
+		{name: "conversion directe", layers: []string{"csv"}, output: "json", want: "json"},
+		{name: "entrée inconnue", output: "json", want: "json"},
+		{name: "format déjà atteint", layers: []string{"csv"}, output: "csv", want: "csv"},
+		{name: "alias", layers: []string{"csv"}, output: "text", want: "txt"},
+		{name: "décompression préalable", layers: []string{"gzip", "csv"}, output: "xml", want: "gunzip+xml"},
+		{name: "deux compressions", layers: []string{"gzip", "gzip", "csv"}, output: "json", want: "gunzip+gunzip+json"},
+		{name: "archive zip", layers: []string{"zip", "csv"}, output: "json", want: "unzip+json"},
+		{name: "compression", layers: []string{"csv"}, output: "gzip", want: "gzip"},
+		{name: "image", layers: []string{"png"}, output: "jpeg", want: "jpeg"},
+		{name: "format sans convertisseur", layers: []string{"tar"}, output: "json", want: "json"},
+		{name: "aucun chemin", layers: []string{"png"}, output: "csv"},
+		{name: "format inconnu", layers: []string{"csv"}, output: "inconnu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := FindRoute(tt.layers, tt.output)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("FindRoute() = %s, erreur attendue", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.String() != tt.want {
+				t.Errorf("FindRoute() = %s, attendu %s", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRoute(t *testing.T) {
+	tests := []struct {
+		layers []string
+		spec   string
+		want   string // Pipeline attendu, "" pour une erreur
+	}{
+		{layers: []string{"csv"}, spec: "json+gzip", want: "json+gzip"},
+		{layers: []string{"gzip", "json"}, spec: "csv+zip", want: "gunzip+csv+zip"},
+		{layers: []string{"csv"}, spec: "gunzip+json"},
+		{layers: []string{"csv"}, spec: "csv+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := PlanRoute(tt.layers, tt.spec)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("PlanRoute() = %s, erreur attendue", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.String() != tt.want {
+				t.Errorf("PlanRoute() = %s, attendu %s", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayers(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers []string
+		spec   string // Couches écrites comme des extensions
+	}{
+		{name: "data.csv", layers: []string{"csv"}, spec: "csv"},
+		{name: "data.csv.gz", layers: []string{"gzip", "csv"}, spec: "csv.gz"},
+		{name: "data.json.gz.gz", layers: []string{"gzip", "gzip", "json"}, spec: "json.gz.gz"},
+		{name: "archive.zip", layers: []string{"zip"}, spec: "zip"},
+		{name: "notes.md.inconnu", spec: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := DefaultRegistry.LayersFromFileName(tt.name)
+			if !reflect.DeepEqual(layers, tt.layers) {
+				t.Fatalf("LayersFromFileName() = %v, attendu %v", layers, tt.layers)
+			}
+			if tt.spec == "" {
+				return
+			}
+			if got := DefaultRegistry.DescribeLayers(layers); got != tt.spec {
+				t.Errorf("DescribeLayers() = %s, attendu %s", got, tt.spec)
+			}
+			parsed, err := ParseLayers(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parsed, tt.layers) {
+				t.Errorf("ParseLayers(%s) = %v, attendu %v", tt.spec, parsed, tt.layers)
+			}
+		})
+	}
+
+	// Seule une compression peut envelopper un autre format
+	if _, err := ParseLayers("csv.json"); err == nil || !strings.Contains(err.Error(), "csv.json") {
+		t.Errorf("ParseLayers(csv.json): erreur = %v", err)
+	}
+}
@@ -1,217 +1,643 @@
 package converter
 
 import (
-	"bytes"
+	"bufio"
+	"context"
 	"encoding/csv"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
+	"io"
 	"strings"
 )
 
 type TextConverter struct{}
 
+func init() {
+	RegisterFormat(Format{Name: "json", Label: "JSON", Category: CategoryText,
+		MIMETypes: []string{"application/json", "text/json"}, Extensions: []string{"json"}})
+	RegisterFormat(Format{Name: "ndjson", Label: "NDJSON", Category: CategoryText, Aliases: []string{"jsonl"},
+		MIMETypes: []string{"application/x-ndjson", "application/jsonl"}, Extensions: []string{"ndjson", "jsonl"}})
+	RegisterFormat(Format{Name: "yaml", Label: "YAML", Category: CategoryText, Aliases: []string{"yml"},
+		MIMETypes: []string{"application/yaml", "application/x-yaml", "text/yaml"}, Extensions: []string{"yaml", "yml"}})
+	RegisterFormat(Format{Name: "toml", Label: "TOML", Category: CategoryText,
+		MIMETypes: []string{"application/toml"}, Extensions: []string{"toml"}})
+	RegisterFormat(Format{Name: "ini", Label: "INI", Category: CategoryText, Aliases: []string{"cfg"},
+		MIMETypes: []string{"text/x-ini"}, Extensions: []string{"ini", "cfg", "conf"}})
+	RegisterFormat(Format{Name: "csv", Label: "CSV", Category: CategoryText,
+		MIMETypes: []string{"text/csv"}, Extensions: []string{"csv"}})
+	RegisterFormat(Format{Name: "tsv", Label: "TSV", Category: CategoryText,
+		MIMETypes: []string{"text/tab-separated-values"}, Extensions: []string{"tsv", "tab"}})
+	RegisterFormat(Format{Name: "psv", Label: "PSV", Category: CategoryText,
+		MIMETypes: []string{"text/x-pipe-separated-values"}, Extensions: []string{"psv"}})
+	RegisterFormat(Format{Name: "xml", Label: "XML", Category: CategoryText,
+		MIMETypes: []string{"application/xml", "text/xml"}, Extensions: []string{"xml"}})
+	RegisterFormat(Format{Name: "txt", Label: "Text", Category: CategoryText, Aliases: []string{"text"},
+		MIMETypes: []string{"text/plain"}, Extensions: []string{"txt"}})
+	RegisterFormat(Format{Name: "md", Label: "Markdown", Category: CategoryText, Aliases: []string{"markdown"},
+		MIMETypes: []string{"text/markdown"}, Extensions: []string{"md", "markdown"}})
+	RegisterFormat(Format{Name: "html", Label: "HTML", Category: CategoryText, Aliases: []string{"htm"},
+		MIMETypes: []string{"text/html"}, Extensions: []string{"html", "htm"}})
+
+	// Le Markdown n'est produit qu'en sortie; le HTML est lu tableau par tableau
+	textFormats := []string{"json", "ndjson", "yaml", "toml", "ini", "csv", "tsv", "psv", "xml", "txt"}
+	RegisterConverter(Registration{
+		Name:    "text",
+		Inputs:  append(append([]string{}, textFormats...), "html"),
+		Outputs: append(append([]string{}, textFormats...), "md", "html"),
+		New:     func() Converter { return &TextConverter{} },
+	})
+}
+
 // GetSupportedFormats retourne les formats supportés
 func (t *TextConverter) GetSupportedFormats() []SupportedFormat {
 	return []SupportedFormat{
 		{Name: "JSON", Extension: "json", ContentType: "application/json"},
+		{Name: "NDJSON", Extension: "ndjson", ContentType: "application/x-ndjson"},
+		{Name: "YAML", Extension: "yaml", ContentType: "application/yaml"},
+		{Name: "TOML", Extension: "toml", ContentType: "application/toml"},
+		{Name: "INI", Extension: "ini", ContentType: "text/x-ini"},
 		{Name: "CSV", Extension: "csv", ContentType: "text/csv"},
+		{Name: "TSV", Extension: "tsv", ContentType: "text/tab-separated-values"},
+		{Name: "PSV", Extension: "psv", ContentType: "text/x-pipe-separated-values"},
 		{Name: "XML", Extension: "xml", ContentType: "application/xml"},
 		{Name: "Text", Extension: "txt", ContentType: "text/plain"},
+		{Name: "Markdown", Extension: "md", ContentType: "text/markdown"},
+		{Name: "HTML", Extension: "html", ContentType: "text/html"},
 	}
 }
 
-// Structure pour la conversion XML
-type XMLData struct {
-	XMLName xml.Name    `xml:"root"`
-	Items   []XMLRecord `xml:"item"`
+func (t *TextConverter) Convert(input []byte, outputFormat string) ([]byte, error) {
+	return convertBytes(t, input, outputFormat)
 }
 
-type XMLRecord struct {
-	Fields []XMLField `xml:"field"`
-}
+// Taille lue à l'avance pour détecter le format d'entrée
+const sniffSize = 4096
 
-type XMLField struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:",chardata"`
-}
+// ConvertStream convertit les enregistrements un par un: les entrées CSV,
+// JSON (tableau), XML et TXT sont lues au fil de l'eau et chaque
+// enregistrement est écrit dès qu'il est lu.
+func (t *TextConverter) ConvertStream(ctx context.Context, r io.Reader, w io.Writer, opts ConvertOptions) error {
+	opts.OutputFormat = canonicalFormat(opts.OutputFormat)
+	// Valider le format de sortie et les options
+	if err := ValidateFormat(opts.OutputFormat, t.GetSupportedFormats()); err != nil {
+		return err
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
 
-func (t *TextConverter) Convert(input []byte, outputFormat string) ([]byte, error) {
-	// Valider le format de sortie
-	if err := ValidateFormat(outputFormat, t.GetSupportedFormats()); err != nil {
-		return nil, err
+	// Décoder l'entrée en UTF-8 et encoder la sortie si demandé
+	br, err := decodeInput(bufio.NewReaderSize(newContextReader(ctx, r), sniffSize), opts.Encoding)
+	if err != nil {
+		return err
 	}
+	out, err := encodeOutput(w, opts.OutputEncoding)
+	if err != nil {
+		return err
+	}
+	w = out
 
-	// Détecter le format d'entrée
-	inputFormat := detectFormat(input)
+	// Détecter le format d'entrée s'il n'est pas imposé
+	inputFormat := opts.InputFormat
+	if f, ok := LookupFormat(inputFormat); ok {
+		inputFormat = f.Name
+	}
+	if inputFormat == "" {
+		head, _ := br.Peek(sniffSize)
+		inputFormat = t.detectFormat(head)
+	}
 	// Vérifier si le format d'entrée est supporté
 	if err := ValidateFormat(inputFormat, t.GetSupportedFormats()); err != nil {
-		return nil, fmt.Errorf("format d'entrée non reconnu: %s", inputFormat)
+		return fmt.Errorf("format d'entrée non reconnu: %s", inputFormat)
 	}
 
-	// Convertir les données en structure intermédiaire
-	var data []map[string]interface{}
+	reader, err := newRecordReader(inputFormat, br, opts)
+	if err != nil {
+		return err
+	}
+	// Colonnes de sortie: celles demandées, sinon celles connues dès l'en-tête
+	columns := opts.Columns
+	if len(columns) == 0 {
+		if cr, ok := reader.(columnReader); ok {
+			columns = cr.Columns()
+		}
+	}
+	writer := newRecordWriter(opts.OutputFormat, w, opts, columns)
 
-	switch inputFormat {
-	case "json":
-		if err := json.Unmarshal(input, &data); err != nil {
-			return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+	for {
+		item, err := reader.Next()
+		if err == io.EOF {
+			break
 		}
-	case "csv":
-		reader := csv.NewReader(bytes.NewReader(input))
-		records, err := reader.ReadAll()
 		if err != nil {
-			return nil, fmt.Errorf("erreur lors du parsing CSV: %v", err)
+			return err
 		}
-
-		if len(records) < 2 {
-			return nil, fmt.Errorf("CSV invalide: besoin d'au moins un en-tête et une ligne de données")
-		}
-
-		headers := records[0]
-		data = make([]map[string]interface{}, 0, len(records)-1)
-
-		for _, record := range records[1:] {
-			item := make(map[string]interface{})
-			for i, value := range record {
-				if i < len(headers) {
-					item[headers[i]] = value
-				}
+		// Reconstruire les valeurs imbriquées d'une entrée à plat
+		// ("address.city"), puis les aplatir si la sortie est à plat
+		if flatInput(inputFormat) {
+			if item, err = Unflatten(item, opts.Flatten); err != nil {
+				return err
 			}
-			data = append(data, item)
-		}
-	case "xml":
-		var xmlData XMLData
-		if err := xml.Unmarshal(input, &xmlData); err != nil {
-			return nil, fmt.Errorf("erreur lors du parsing XML: %v", err)
 		}
-
-		data = make([]map[string]interface{}, len(xmlData.Items))
-		for i, item := range xmlData.Items {
-			record := make(map[string]interface{})
-			for _, field := range item.Fields {
-				record[field.Name] = field.Value
+		if opts.Types.enabled() {
+			if item, err = opts.Types.applyTypes(item, opts.Flatten.separator(), untypedInput(inputFormat)); err != nil {
+				return err
 			}
-			data[i] = record
 		}
-	case "txt":
-		lines := strings.Split(string(input), "\n")
-		data = make([]map[string]interface{}, len(lines))
-		
-		for i, line := range lines {
-			line = strings.TrimSpace(line)
-			if line != "" {
-				data[i] = map[string]interface{}{
-					"line": line,
-				}
-			}
+		if flatOutput(opts.OutputFormat) {
+			item = Flatten(item, opts.Flatten)
 		}
-		
-		cleanData := []map[string]interface{}{}
-		for _, item := range data {
-			if item != nil {
-				cleanData = append(cleanData, item)
-			}
+		if len(opts.Columns) > 0 {
+			item = item.project(opts.Columns)
+		}
+		if err := writer.Write(item); err != nil {
+			return err
 		}
-		data = cleanData
 	}
 
-	// Convertir vers le format de sortie
-	switch outputFormat {
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// untypedInput indique si un format d'entrée ne lit que des chaînes
+func untypedInput(format string) bool {
+	return delimitedFormat(format) || format == "xml" || format == "txt" || format == "ini" || format == "html"
+}
+
+// flatInput indique si un format d'entrée contient des valeurs aplaties
+// par Flatten, à reconstruire
+func flatInput(format string) bool {
+	return delimitedFormat(format) || format == "txt" || format == "html"
+}
+
+// flatOutput indique si un format de sortie ne sait pas représenter les
+// valeurs imbriquées
+func flatOutput(format string) bool {
+	return delimitedFormat(format) || format == "txt" || format == "md" || format == "html"
+}
+
+// recordReader lit les enregistrements un par un; Next retourne io.EOF à la fin
+type recordReader interface {
+	Next() (*Record, error)
+}
+
+// columnReader est implémenté par les lecteurs dont les colonnes sont
+// connues avant la première ligne (en-tête CSV)
+type columnReader interface {
+	Columns() []string
+}
+
+// recordWriter écrit les enregistrements un par un; Close termine le document
+type recordWriter interface {
+	Write(item *Record) error
+	Close() error
+}
+
+func newRecordReader(format string, r io.Reader, opts ConvertOptions) (recordReader, error) {
+	switch format {
+	case "json":
+		return newJSONReader(r)
+	case "ndjson":
+		return newNDJSONReader(r), nil
+	case "yaml":
+		return newYAMLReader(r), nil
+	case "toml":
+		return newTOMLReader(r)
+	case "ini":
+		return &iniReader{scanner: newLineScanner(r)}, nil
+	case "csv", "tsv", "psv":
+		return newCSVReader(r, opts.readOptions(format))
+	case "xml":
+		return newXMLReader(r, opts.XML)
+	case "txt":
+		return newTXTReader(r, opts.TXT), nil
+	case "html":
+		return newHTMLReader(r, opts.HTML)
+	}
+	return nil, fmt.Errorf("format d'entrée non reconnu: %s", format)
+}
+
+func newRecordWriter(format string, w io.Writer, opts ConvertOptions, columns []string) recordWriter {
+	switch format {
 	case "json":
-		return json.MarshalIndent(data, "", "  ")
-	case "csv":
-		if len(data) == 0 {
-			return nil, fmt.Errorf("pas de données à convertir")
-		}
+		return &jsonWriter{w: w, opts: opts.JSON}
+	case "ndjson":
+		return &ndjsonWriter{w: w}
+	case "yaml":
+		return &yamlWriter{w: w}
+	case "toml":
+		return &tomlWriter{w: w}
+	case "ini":
+		return &iniWriter{w: w}
+	case "csv", "tsv", "psv":
+		return newCSVWriter(w, opts.writeOptions(format), columns)
+	case "xml":
+		return &xmlWriter{w: w, opts: opts.XML, encoding: encodingLabel(opts.OutputEncoding)}
+	case "md":
+		return &mdWriter{w: w, table: Table{Columns: columns}}
+	case "html":
+		return newHTMLWriter(w, columns)
+	default:
+		return newTXTWriter(w, opts.TXT, columns)
+	}
+}
 
-		headers := make([]string, 0)
-		for k := range data[0] {
-			headers = append(headers, k)
-		}
+// jsonReader lit un document JSON quelconque. Un tableau est parcouru
+// élément par élément; un objet ou une valeur seule donne un enregistrement.
+type jsonReader struct {
+	decoder *json.Decoder
+	single  *Record // Enregistrement unique d'un document qui n'est pas un tableau
+	done    bool
+}
 
-		buf := new(bytes.Buffer)
-		writer := csv.NewWriter(buf)
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	tok, err := decoder.Token()
+	if err == io.EOF {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: document vide")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+	}
+	if delim, ok := tok.(json.Delim); ok && delim == '[' {
+		return &jsonReader{decoder: decoder}, nil
+	}
 
-		if err := writer.Write(headers); err != nil {
-			return nil, fmt.Errorf("erreur lors de l'écriture des en-têtes CSV: %v", err)
+	// Objet ou valeur seule: lu en entier, puis rien ne doit suivre
+	value, err := decodeJSONToken(decoder, tok)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: données en trop après le document (format ndjson ?)")
+	}
+	return &jsonReader{decoder: decoder, single: jsonRecord(value)}, nil
+}
+
+func (j *jsonReader) Next() (*Record, error) {
+	if j.done {
+		return nil, io.EOF
+	}
+	if j.single != nil {
+		j.done = true
+		return j.single, nil
+	}
+	if !j.decoder.More() {
+		j.done = true
+		if _, err := j.decoder.Token(); err != nil {
+			return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
 		}
+		return nil, io.EOF
+	}
 
-		for _, item := range data {
-			record := make([]string, len(headers))
-			for i, header := range headers {
-				if val, ok := item[header]; ok {
-					record[i] = fmt.Sprint(val)
-				}
-			}
-			if err := writer.Write(record); err != nil {
-				return nil, fmt.Errorf("erreur lors de l'écriture des données CSV: %v", err)
-			}
+	value, err := decodeJSONValue(j.decoder)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing JSON: %v", err)
+	}
+	return jsonRecord(value), nil
+}
+
+// jsonRecord associe une valeur JSON à un enregistrement: un objet tel quel,
+// une liste en colonnes positionnelles (col1, col2...), une valeur simple
+// dans une colonne "value"
+func jsonRecord(value interface{}) *Record {
+	switch v := value.(type) {
+	case *Record:
+		return v
+	case []interface{}:
+		item := NewRecord()
+		for i, elem := range v {
+			item.Set(columnName(i), elem)
 		}
+		return item
+	default:
+		item := NewRecord()
+		item.Set("value", v)
+		return item
+	}
+}
+
+// ndjsonReader lit une valeur JSON par ligne (NDJSON, JSON Lines)
+type ndjsonReader struct {
+	decoder *json.Decoder
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &ndjsonReader{decoder: decoder}
+}
+
+func (n *ndjsonReader) Next() (*Record, error) {
+	value, err := decodeJSONValue(n.decoder)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	n.line++
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing NDJSON (enregistrement %d): %v", n.line, err)
+	}
+	return jsonRecord(value), nil
+}
 
-		writer.Flush()
-		if err := writer.Error(); err != nil {
-			return nil, fmt.Errorf("erreur lors de la finalisation du CSV: %v", err)
+// csvReader lit une ligne CSV à la fois. Le dialecte qui n'est pas imposé
+// par les options est détecté sur le début du contenu.
+type csvReader struct {
+	reader  *csv.Reader
+	swap    quoteSwap
+	headers []string
+	rows    int
+}
+
+func newCSVReader(r io.Reader, opts CSVOptions) (*csvReader, error) {
+	if opts.Delimiter == 0 || opts.Quote == 0 || (!opts.Header && !opts.NoHeader) {
+		br := bufio.NewReaderSize(r, sniffSize)
+		head, _ := br.Peek(sniffSize)
+		opts = opts.withDialect(SniffDialect(head, len(head) >= sniffSize))
+		r = br
+	}
+
+	swap := quoteSwap(opts.quote())
+	reader := csv.NewReader(swap.reader(r))
+	reader.Comma = swap.rune(opts.delimiter())
+	c := &csvReader{reader: reader, swap: swap}
+	if opts.NoHeader {
+		// Les noms de colonnes sont générés à la lecture de la première ligne
+		return c, nil
+	}
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV invalide: besoin d'au moins un en-tête et une ligne de données")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing CSV: %v", err)
+	}
+	c.headers = swap.strings(headers)
+	return c, nil
+}
+
+func (c *csvReader) Next() (*Record, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		if c.rows == 0 {
+			if c.headers == nil {
+				return nil, fmt.Errorf("CSV invalide: aucune ligne de données")
+			}
+			return nil, fmt.Errorf("CSV invalide: besoin d'au moins un en-tête et une ligne de données")
 		}
-		return buf.Bytes(), nil
-	case "xml":
-		xmlData := XMLData{
-			Items: make([]XMLRecord, len(data)),
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing CSV: %v", err)
+	}
+	c.rows++
+	if c.headers == nil {
+		c.headers = make([]string, len(record))
+		for i := range record {
+			c.headers[i] = columnName(i)
 		}
+	}
 
-		for i, item := range data {
-			var fields []XMLField
-			for key, value := range item {
-				fields = append(fields, XMLField{
-					Name:  key,
-					Value: fmt.Sprint(value),
-				})
-			}
-			xmlData.Items[i] = XMLRecord{Fields: fields}
+	item := NewRecord()
+	for i, value := range c.swap.strings(record) {
+		if i < len(c.headers) {
+			item.Set(c.headers[i], value)
 		}
+	}
+	return item, nil
+}
 
-		buf := new(bytes.Buffer)
-		buf.WriteString(xml.Header)
+// Columns retourne les colonnes de l'en-tête
+func (c *csvReader) Columns() []string {
+	return c.headers
+}
 
-		encoder := xml.NewEncoder(buf)
-		encoder.Indent("", "  ")
-		if err := encoder.Encode(xmlData); err != nil {
-			return nil, fmt.Errorf("erreur lors de l'encodage XML: %v", err)
-		}
+// quoteSwap permet d'utiliser encoding/csv, qui ne connaît que '"', avec un
+// autre caractère de citation: ce caractère et '"' sont échangés dans le flux
+// lu ou écrit, puis dans les valeurs. L'échange étant son propre inverse, le
+// résultat est exact. Le caractère est ASCII et ne peut donc pas apparaître à
+// l'intérieur d'un caractère UTF-8 multi-octets.
+type quoteSwap byte
 
-		return buf.Bytes(), nil
-	case "txt":
-		var builder strings.Builder
-		for _, item := range data {
-			for key, value := range item {
-				builder.WriteString(fmt.Sprintf("%s: %v\n", key, value))
-			}
-			builder.WriteString("\n")
+func (q quoteSwap) active() bool {
+	return q != '"'
+}
+
+func (q quoteSwap) rune(r rune) rune {
+	switch r {
+	case rune(q):
+		return '"'
+	case '"':
+		return rune(q)
+	}
+	return r
+}
+
+func (q quoteSwap) bytes(p []byte) {
+	for i, c := range p {
+		switch c {
+		case byte(q):
+			p[i] = '"'
+		case '"':
+			p[i] = byte(q)
 		}
-		return []byte(builder.String()), nil
 	}
+}
 
-	return nil, nil
+func (q quoteSwap) strings(values []string) []string {
+	if !q.active() {
+		return values
+	}
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[i] = strings.Map(q.rune, value)
+	}
+	return out
 }
 
-func detectFormat(input []byte) string {
-	trimmed := bytes.TrimSpace(input)
-	if len(trimmed) == 0 {
-		return ""
+func (q quoteSwap) reader(r io.Reader) io.Reader {
+	if !q.active() {
+		return r
 	}
+	return &swapReader{r: r, swap: q}
+}
 
-	if trimmed[0] == '{' || trimmed[0] == '[' {
-		return "json"
+func (q quoteSwap) writer(w io.Writer) io.Writer {
+	if !q.active() {
+		return w
 	}
+	return &swapWriter{w: w, swap: q}
+}
+
+type swapReader struct {
+	r    io.Reader
+	swap quoteSwap
+}
+
+func (s *swapReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.swap.bytes(p[:n])
+	return n, err
+}
+
+type swapWriter struct {
+	w    io.Writer
+	swap quoteSwap
+	buf  []byte
+}
+
+func (s *swapWriter) Write(p []byte) (int, error) {
+	s.buf = append(s.buf[:0], p...)
+	s.swap.bytes(s.buf)
+	return s.w.Write(s.buf)
+}
 
-	if trimmed[0] == '<' {
-		return "xml"
+// jsonWriter écrit un tableau JSON indenté, élément par élément
+type jsonWriter struct {
+	w     io.Writer
+	opts  JSONOptions
+	count int
+}
+
+func (j *jsonWriter) Write(item *Record) error {
+	var (
+		data []byte
+		err  error
+		sep  string
+	)
+	if j.opts.Compact {
+		data, err = json.Marshal(item)
+		sep = ","
+	} else {
+		indent := j.opts.indent()
+		data, err = json.MarshalIndent(item, indent, indent)
+		sep = ",\n" + indent
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'encodage JSON: %v", err)
+	}
+
+	if j.count == 0 {
+		sep = "[" + sep[1:]
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]"
+	switch {
+	case j.count == 0:
+		end = "[]"
+	case j.opts.Compact:
+		end = "]"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// ndjsonWriter écrit un objet JSON compact par ligne
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonWriter) Write(item *Record) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'encodage NDJSON: %v", err)
+	}
+	data = append(data, '\n')
+	_, err = n.w.Write(data)
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// csvWriter écrit une ligne d'en-têtes puis une ligne par enregistrement.
+// Lorsque les colonnes ne sont pas connues d'avance, elles sont l'union des
+// champs de tous les enregistrements, dans l'ordre d'apparition: les
+// enregistrements sont alors gardés en mémoire jusqu'à la fin de la lecture.
+type csvWriter struct {
+	writer   *csv.Writer
+	swap     quoteSwap
+	noHeader bool
+	table    Table
+	fixed    bool
+	started  bool
+}
+
+func newCSVWriter(w io.Writer, opts CSVOptions, columns []string) *csvWriter {
+	swap := quoteSwap(opts.quote())
+	writer := csv.NewWriter(swap.writer(w))
+	writer.Comma = swap.rune(opts.delimiter())
+	writer.UseCRLF = opts.CRLF
+	return &csvWriter{
+		writer:   writer,
+		swap:     swap,
+		noHeader: opts.NoHeader,
+		table:    Table{Columns: columns},
+		fixed:    len(columns) > 0,
+	}
+}
+
+func (c *csvWriter) Write(item *Record) error {
+	if !c.fixed {
+		c.table.Append(item)
+		return nil
+	}
+	return c.writeRow(item)
+}
+
+func (c *csvWriter) writeRow(item *Record) error {
+	if !c.started {
+		c.started = true
+		if !c.noHeader {
+			if err := c.writer.Write(c.swap.strings(c.table.Columns)); err != nil {
+				return fmt.Errorf("erreur lors de l'écriture des en-têtes CSV: %v", err)
+			}
+		}
+	}
+
+	if err := c.writer.Write(c.swap.strings(c.table.Row(item))); err != nil {
+		return fmt.Errorf("erreur lors de l'écriture des données CSV: %v", err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	for _, item := range c.table.Records {
+		if err := c.writeRow(item); err != nil {
+			return err
+		}
+	}
+	if !c.started {
+		return fmt.Errorf("pas de données à convertir")
 	}
 
-	firstLine := bytes.SplitN(trimmed, []byte{'\n'}, 2)[0]
-	if bytes.Count(firstLine, []byte{','}) > 0 && !bytes.ContainsAny(firstLine, "{}[]<>") {
-		return "csv"
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return fmt.Errorf("erreur lors de la finalisation du CSV: %v", err)
 	}
+	return nil
+}
 
-	return "txt"
-}
\ No newline at end of file
+// detectFormat retourne le format d'entrée le plus probable parmi ceux gérés
+func (t *TextConverter) detectFormat(head []byte) string {
+	for _, d := range DetectAll(head) {
+		if ValidateFormat(d.Format, t.GetSupportedFormats()) == nil {
+			return d.Format
+		}
+	}
+	return ""
+}
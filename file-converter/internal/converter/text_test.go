@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// convertText convertit input avec le convertisseur texte
+func convertText(input string, opts ConvertOptions) (string, error) {
+	var out bytes.Buffer
+	err := (&TextConverter{}).ConvertStream(context.Background(), strings.NewReader(input), &out, opts)
+	return out.String(), err
+}
+
+// textTest est un cas de conversion: sortie attendue, ou début du message
+// d'erreur attendu
+type textTest struct {
+	name    string
+	input   string
+	opts    ConvertOptions
+	want    string
+	wantErr string
+}
+
+func runTextTests(t *testing.T, tests []textTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertText(tt.input, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur = %v, attendu %s...", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("sortie = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertOptions(t *testing.T) {
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "format détecté", input: "nom\nA\n", opts: ConvertOptions{OutputFormat: "json", JSON: compact},
+			want: `[{"line":"nom"},{"line":"A"}]`},
+		{name: "format imposé", input: "nom\nA\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", JSON: compact},
+			want: `[{"nom":"A"}]`},
+		{name: "alias du format imposé", input: "nom\nA\n", opts: ConvertOptions{InputFormat: "CSV", OutputFormat: "json", JSON: compact},
+			want: `[{"nom":"A"}]`},
+		{name: "séparateur", input: "a;b\n1;2\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", CSV: CSVOptions{Delimiter: ';'}, JSON: compact},
+			want: `[{"a":"1","b":"2"}]`},
+		{name: "citation", input: "a,b\n'x,y',2\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", CSV: CSVOptions{Quote: '\''}, JSON: compact},
+			want: `[{"a":"x,y","b":"2"}]`},
+		{name: "sans en-tête", input: "1,2\n3,4\n", opts: ConvertOptions{InputFormat: "csv", OutputFormat: "json", CSV: CSVOptions{NoHeader: true}, JSON: compact},
+			want: `[{"col1":"1","col2":"2"},{"col1":"3","col2":"4"}]`},
+		{name: "écriture CSV", input: `[{"a":"x;y","b":1}]`, opts: ConvertOptions{OutputFormat: "csv", CSV: CSVOptions{Delimiter: ';', NoHeader: true}},
+			want: "\"x;y\";1\n"},
+		{name: "indentation JSON", input: `[{"a":1}]`, opts: ConvertOptions{OutputFormat: "json", JSON: JSONOptions{Indent: "\t"}},
+			want: "[\n\t{\n\t\t\"a\": 1\n\t}\n]"},
+		{name: "noms XML", input: `{"a":"1"}`, opts: ConvertOptions{OutputFormat: "xml", XML: XMLOptions{Root: "livres", Item: "livre", Compact: true}},
+			want: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<livres><livre><field name=\"a\">1</field></livre></livres>"},
+		{name: "racine XML vérifiée", input: `<r><i>1</i></r>`, opts: ConvertOptions{OutputFormat: "json", XML: XMLOptions{Root: "x"}},
+			wantErr: "erreur lors du parsing XML"},
+		{name: "en-tête incompatible", input: `[{"a":1}]`, opts: ConvertOptions{OutputFormat: "csv", CSV: CSVOptions{Header: true, NoHeader: true}},
+			wantErr: "les options en-tête et sans en-tête sont incompatibles"},
+		{name: "citation identique au séparateur", input: `[{"a":1}]`, opts: ConvertOptions{OutputFormat: "csv", CSV: CSVOptions{Delimiter: ';', Quote: ';'}},
+			wantErr: "le séparateur et le caractère de citation doivent être différents"},
+		{name: "nom XML invalide", input: `[{"a":1}]`, opts: ConvertOptions{OutputFormat: "xml", XML: XMLOptions{Root: "a b"}},
+			wantErr: "nom d'élément XML invalide"},
+		{name: "format d'entrée inconnu", input: `[{"a":1}]`, opts: ConvertOptions{InputFormat: "inconnu", OutputFormat: "csv"},
+			wantErr: "format d'entrée non reconnu"},
+	})
+}
+
+func TestColumns(t *testing.T) {
+	runTextTests(t, []textTest{
+		{name: "union dans l'ordre d'apparition", input: `[{"b":1,"a":2},{"c":3,"a":4}]`, opts: ConvertOptions{OutputFormat: "csv"},
+			want: "b,a,c\n1,2,\n,4,3\n"},
+		{name: "colonnes choisies", input: `[{"b":1,"a":2},{"c":3,"a":4}]`, opts: ConvertOptions{OutputFormat: "csv", Columns: []string{"c", "b"}},
+			want: "c,b\n,1\n3,\n"},
+		{name: "colonnes choisies en JSON", input: `[{"b":1,"a":2},{"c":3,"a":4}]`, opts: ConvertOptions{OutputFormat: "json", Columns: []string{"c", "x"}, JSON: JSONOptions{Compact: true}},
+			want: `[{},{"c":3}]`},
+		{name: "ordre XML conservé", input: `<r><i><z>1</z><a>2</a></i><i><y>3</y></i></r>`, opts: ConvertOptions{OutputFormat: "csv"},
+			want: "z,a,y\n1,2,\n,,3\n"},
+	})
+}
+
+func TestJSONDocuments(t *testing.T) {
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "objet seul", input: `{"a":1,"b":{"c":2}}`, opts: ConvertOptions{OutputFormat: "csv"},
+			want: "a,b.c\n1,2\n"},
+		{name: "liste de valeurs", input: `[1,"x",null]`, opts: ConvertOptions{OutputFormat: "csv"},
+			want: "value\n1\nx\n\n"},
+		{name: "liste de listes", input: `[[1,2],[3,4,5]]`, opts: ConvertOptions{OutputFormat: "csv"},
+			want: "col1,col2,col3\n1,2,\n3,4,5\n"},
+		{name: "valeur seule", input: `"txt"`, opts: ConvertOptions{InputFormat: "json", OutputFormat: "json", JSON: compact},
+			want: `[{"value":"txt"}]`},
+		{name: "liste vide", input: `[]`, opts: ConvertOptions{OutputFormat: "json", JSON: compact},
+			want: `[]`},
+		{name: "liste mixte", input: `[{"a":1},2]`, opts: ConvertOptions{OutputFormat: "json", JSON: compact},
+			want: `[{"a":1},{"value":2}]`},
+		{name: "données en trop", input: `{"a":1} x`, opts: ConvertOptions{InputFormat: "json", OutputFormat: "csv"},
+			wantErr: "erreur lors du parsing JSON: données en trop après le document (format ndjson ?)"},
+	})
+}
+
+func TestNDJSON(t *testing.T) {
+	runTextTests(t, []textTest{
+		{name: "lecture", input: "{\"a\":1}\n{\"b\":2}\n", opts: ConvertOptions{OutputFormat: "csv"},
+			want: "a,b\n1,\n,2\n"},
+		{name: "lignes vides et valeurs", input: "{\"a\":1}\n\n[1,2]\n3\n", opts: ConvertOptions{InputFormat: "ndjson", OutputFormat: "json", JSON: JSONOptions{Compact: true}},
+			want: `[{"a":1},{"col1":1,"col2":2},{"value":3}]`},
+		{name: "écriture", input: `[{"a":1,"b":{"c":[1,2]}},{"d":null}]`, opts: ConvertOptions{OutputFormat: "ndjson"},
+			want: "{\"a\":1,\"b\":{\"c\":[1,2]}}\n{\"d\":null}\n"},
+		{name: "ligne invalide", input: "{\"a\":1}\n{\"b\":\n", opts: ConvertOptions{InputFormat: "ndjson", OutputFormat: "csv"},
+			wantErr: "erreur lors du parsing NDJSON (enregistrement 2)"},
+	})
+}
@@ -0,0 +1,370 @@
+// internal/converter/toml.go
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlRecordsKey est la table utilisée pour écrire plusieurs enregistrements
+// ([[records]]); à la lecture, un document qui ne contient qu'un tableau de
+// tables donne un enregistrement par table, quel que soit son nom
+const tomlRecordsKey = "records"
+
+// tomlReader lit un document TOML. Les tables deviennent des objets imbriqués.
+type tomlReader struct {
+	records []*Record
+}
+
+func newTOMLReader(r io.Reader) (*tomlReader, error) {
+	var doc map[string]interface{}
+	meta, err := toml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du parsing TOML: %v", err)
+	}
+
+	// Position de chaque clé dans le document, pour en garder l'ordre
+	order := make(map[string]int)
+	for i, key := range meta.Keys() {
+		path := key.String()
+		if _, ok := order[path]; !ok {
+			order[path] = i
+		}
+	}
+	root := tomlValue(doc, "", order).(*Record)
+
+	// Un document limité à un tableau de tables donne un enregistrement par table
+	if root.Len() == 1 {
+		value, _ := root.Get(root.Keys()[0])
+		if list, ok := value.([]interface{}); ok && len(list) > 0 && allRecords(list) {
+			records := make([]*Record, len(list))
+			for i, elem := range list {
+				records[i] = elem.(*Record)
+			}
+			return &tomlReader{records: records}, nil
+		}
+	}
+	return &tomlReader{records: []*Record{root}}, nil
+}
+
+func (t *tomlReader) Next() (*Record, error) {
+	if len(t.records) == 0 {
+		return nil, io.EOF
+	}
+	item := t.records[0]
+	t.records = t.records[1:]
+	return item, nil
+}
+
+// tomlValue convertit une valeur décodée vers le modèle de Record; path est
+// le chemin de la valeur, qui sert à retrouver l'ordre des clés
+func tomlValue(value interface{}, path string, order map[string]int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		position := func(key string) int {
+			if i, ok := order[toml.Key(append(splitTOMLPath(path), key)).String()]; ok {
+				return i
+			}
+			return math.MaxInt
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			pi, pj := position(keys[i]), position(keys[j])
+			if pi != pj {
+				return pi < pj
+			}
+			return keys[i] < keys[j]
+		})
+
+		record := NewRecord()
+		for _, key := range keys {
+			child := toml.Key(append(splitTOMLPath(path), key)).String()
+			record.Set(key, tomlValue(v[key], child, order))
+		}
+		return record
+	case []map[string]interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = tomlValue(elem, path, order)
+		}
+		return list
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = tomlValue(elem, path, order)
+		}
+		return list
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return json.Number(strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		return tomlDate(v)
+	}
+	return value
+}
+
+// tomlDate garde le format d'origine des dates locales TOML
+func tomlDate(t time.Time) interface{} {
+	switch t.Location().String() {
+	case "date-local":
+		return Date{Time: t, Text: t.Format("2006-01-02")}
+	case "datetime-local":
+		return Date{Time: t, Text: t.Format("2006-01-02T15:04:05.999999999")}
+	case "time-local":
+		// Une heure seule n'est pas une date: gardée comme texte
+		return t.Format("15:04:05.999999999")
+	}
+	return Date{Time: t, Text: t.Format(time.RFC3339Nano)}
+}
+
+// splitTOMLPath découpe un chemin produit par toml.Key.String
+func splitTOMLPath(path string) toml.Key {
+	if path == "" {
+		return nil
+	}
+	// Les chemins sont reconstruits à partir de clés déjà découpées: seul le
+	// cas des clés entre guillemets contenant un point demande de l'attention
+	var key toml.Key
+	var current strings.Builder
+	quoted := false
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+			current.WriteByte(c)
+		case c == '\\' && quoted && i+1 < len(path):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(path[i])
+		case c == '.' && !quoted:
+			key = append(key, unquoteTOMLKey(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return append(key, unquoteTOMLKey(current.String()))
+}
+
+func unquoteTOMLKey(key string) string {
+	if unquoted, err := strconv.Unquote(key); err == nil {
+		return unquoted
+	}
+	return key
+}
+
+func allRecords(list []interface{}) bool {
+	for _, elem := range list {
+		if _, ok := elem.(*Record); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlWriter écrit un enregistrement comme un document TOML, ou plusieurs
+// enregistrements comme un tableau de tables [[records]]. Le premier
+// enregistrement est gardé en attente pour savoir s'il est seul.
+type tomlWriter struct {
+	w       io.Writer
+	pending *Record
+	count   int
+	written bool
+}
+
+func (t *tomlWriter) Write(item *Record) error {
+	t.count++
+	switch t.count {
+	case 1:
+		t.pending = item
+		return nil
+	case 2:
+		if err := t.writeTable(t.pending, true); err != nil {
+			return err
+		}
+		t.pending = nil
+	}
+	return t.writeTable(item, true)
+}
+
+func (t *tomlWriter) Close() error {
+	if t.pending != nil {
+		return t.writeTable(t.pending, false)
+	}
+	return nil
+}
+
+func (t *tomlWriter) writeTable(item *Record, inArray bool) error {
+	var buf bytes.Buffer
+	var path []string
+	if inArray {
+		path = []string{tomlRecordsKey}
+		if t.written {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("[[" + tomlRecordsKey + "]]\n")
+	}
+	if err := writeTOMLTable(&buf, item, path); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage TOML: %v", err)
+	}
+	t.written = true
+	_, err := t.w.Write(buf.Bytes())
+	return err
+}
+
+// writeTOMLTable écrit les paires simples d'une table, puis ses sous-tables
+// et ses tableaux de tables. Les valeurs nulles, absentes de TOML, sont omises.
+func writeTOMLTable(buf *bytes.Buffer, item *Record, path []string) error {
+	var tables, arrays []string
+	for _, key := range item.Keys() {
+		value, _ := item.Get(key)
+		switch v := value.(type) {
+		case nil:
+			continue
+		case *Record:
+			tables = append(tables, key)
+			continue
+		case []interface{}:
+			if len(v) > 0 && allRecords(v) {
+				arrays = append(arrays, key)
+				continue
+			}
+		}
+		text, err := tomlInline(value)
+		if err != nil {
+			return fmt.Errorf("clé %s: %v", key, err)
+		}
+		buf.WriteString(tomlKey(key) + " = " + text + "\n")
+	}
+
+	for _, key := range tables {
+		value, _ := item.Get(key)
+		child := append(append([]string(nil), path...), key)
+		buf.WriteString("\n[" + tomlPath(child) + "]\n")
+		if err := writeTOMLTable(buf, value.(*Record), child); err != nil {
+			return err
+		}
+	}
+	for _, key := range arrays {
+		value, _ := item.Get(key)
+		child := append(append([]string(nil), path...), key)
+		for _, elem := range value.([]interface{}) {
+			buf.WriteString("\n[[" + tomlPath(child) + "]]\n")
+			if err := writeTOMLTable(buf, elem.(*Record), child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tomlInline écrit une valeur sur une ligne: tableaux et tables en ligne
+func tomlInline(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("valeur nulle dans un tableau")
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case Date:
+		return v.Text, nil
+	case string:
+		return tomlString(v), nil
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, elem := range v {
+			text, err := tomlInline(elem)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = text
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case *Record:
+		var parts []string
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			if child == nil {
+				continue
+			}
+			text, err := tomlInline(child)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tomlKey(key)+" = "+text)
+		}
+		if len(parts) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+	return tomlString(FormatValue(value)), nil
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+func tomlPath(path []string) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = tomlKey(key)
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlString écrit une chaîne TOML de base, entre guillemets
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
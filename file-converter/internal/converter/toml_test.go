@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTOMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // Relecture attendue en JSON
+	}{
+		{name: "document", input: `{"nom":"app","port":8080,"ratio":0.5,"actif":true,"tags":["a","b"]}`,
+			want: `[{"nom":"app","port":8080,"ratio":0.5,"actif":true,"tags":["a","b"]}]`},
+		{name: "tables", input: `{"db":{"hôte":"localhost","pool":{"max":10}},"log":{}}`,
+			want: `[{"db":{"hôte":"localhost","pool":{"max":10}},"log":{}}]`},
+		{name: "clés à guillemets", input: `{"a.b":{"c d":"1"},"a=b":"2","":"3"}`,
+			want: `[{"a=b":"2","":"3","a.b":{"c d":"1"}}]`},
+		{name: "plusieurs enregistrements", input: `[{"id":1,"nom":"a"},{"id":2,"nom":"b"}]`,
+			want: `[{"id":1,"nom":"a"},{"id":2,"nom":"b"}]`},
+		{name: "tableau de tables", input: `{"n":1,"items":[{"a":1},{"a":2}]}`,
+			want: `[{"n":1,"items":[{"a":1},{"a":2}]}]`},
+		{name: "valeurs nulles omises", input: `{"a":null,"b":"x"}`, want: `[{"b":"x"}]`},
+		{name: "chaînes à échapper", input: `{"a":"ligne\nsuite \"cité\" \\ \t"}`,
+			want: `[{"a":"ligne\nsuite \"cité\" \\ \t"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, got := roundTrip(t, tt.input, "toml")
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("relecture de %q = %v, attendu %v", text, got, want)
+			}
+		})
+	}
+}
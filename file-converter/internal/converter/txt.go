@@ -0,0 +1,409 @@
+// internal/converter/txt.go
+package converter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// txtLines désigne un fichier TXT lu ligne par ligne: {"line": ...}
+const txtLines = "lines"
+
+// txtRuleLine reconnaît le soulignement de l'en-tête d'un tableau aligné
+var txtRuleLine = regexp.MustCompile(`^-+( +-+)*\s*$`)
+
+// Indentation des lignes de suite d'une valeur sur plusieurs lignes
+const txtContinuation = "  "
+
+// sniffTXTLayout devine la mise en forme d'un début de fichier TXT: tableau
+// aligné si la deuxième ligne souligne l'en-tête, blocs "clé: valeur" si
+// toutes les lignes en sont, lignes simples sinon. blocks compte les blocs
+// séparés par une ligne vide.
+func sniffTXTLayout(head []byte, truncated bool) (layout string, blocks int) {
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+	if truncated && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) != "" && txtRuleLine.MatchString(lines[1]) {
+		return TXTTable, 0
+	}
+
+	keys, open := 0, false
+	for _, line := range lines {
+		switch {
+		case open && strings.HasPrefix(line, txtContinuation):
+		case strings.TrimSpace(line) == "":
+			open = false
+		case isTXTKeyLine(line):
+			if !open {
+				blocks++
+			}
+			keys++
+			open = true
+		default:
+			return txtLines, 0
+		}
+	}
+	if keys == 0 {
+		return txtLines, 0
+	}
+	return TXTBlocks, blocks
+}
+
+// Longueur maximale d'une ligne lue par newLineScanner
+const maxLineSize = 16 * 1024 * 1024
+
+// newLineScanner lit un texte ligne par ligne, au-delà de la limite de 64 Ko
+// de bufio.Scanner
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return scanner
+}
+
+// newTXTReader choisit le lecteur d'après les options ou la mise en forme détectée
+func newTXTReader(r io.Reader, opts TXTOptions) recordReader {
+	br := bufio.NewReaderSize(r, sniffSize)
+	scanner := newLineScanner(br)
+	if len(opts.Widths) > 0 {
+		return &fixedReader{scanner: scanner, columns: opts.Widths}
+	}
+
+	head, _ := br.Peek(sniffSize)
+	layout, _ := sniffTXTLayout(head, len(head) >= sniffSize)
+	switch layout {
+	case TXTTable:
+		return &fixedReader{scanner: scanner, header: true}
+	case TXTBlocks:
+		return &blockReader{scanner: scanner}
+	}
+	return &txtReader{scanner: scanner}
+}
+
+// txtReader produit un enregistrement par ligne non vide
+type txtReader struct {
+	scanner *bufio.Scanner
+}
+
+func (t *txtReader) Next() (*Record, error) {
+	for t.scanner.Scan() {
+		line := strings.TrimSpace(t.scanner.Text())
+		if line != "" {
+			item := NewRecord()
+			item.Set("line", line)
+			return item, nil
+		}
+	}
+	if err := t.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture du texte: %v", err)
+	}
+	return nil, io.EOF
+}
+
+// blockReader relit les blocs "clé: valeur" de txtWriter: un enregistrement
+// par bloc, les blocs étant séparés par une ligne vide
+type blockReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (b *blockReader) Next() (*Record, error) {
+	var item *Record
+	key := ""
+	for b.scanner.Scan() {
+		b.line++
+		line := strings.TrimRight(b.scanner.Text(), "\r")
+		// Suite d'une valeur sur plusieurs lignes
+		if item != nil && strings.HasPrefix(line, txtContinuation) {
+			value, _ := item.Get(key)
+			item.Set(key, value.(string)+"\n"+line[len(txtContinuation):])
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			if item != nil {
+				return item, nil
+			}
+			continue
+		}
+		var value string
+		var ok bool
+		if key, value, ok = cutTXTKey(line); !ok {
+			return nil, fmt.Errorf("erreur lors de la lecture du texte: ligne %d: \"clé: valeur\" attendu", b.line)
+		}
+		if item == nil {
+			item = NewRecord()
+		}
+		item.Set(key, value)
+	}
+	if err := b.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture du texte: %v", err)
+	}
+	if item != nil {
+		return item, nil
+	}
+	return nil, io.EOF
+}
+
+// cutTXTKey sépare une ligne "clé: valeur" écrite par txtWriter. La clé
+// s'arrête au premier ": " ou au ":" final d'une valeur vide; une clé entre
+// guillemets est relue avec ses échappements.
+func cutTXTKey(line string) (key, value string, ok bool) {
+	if strings.HasPrefix(line, `"`) {
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return "", "", false
+		}
+		key, _ = strconv.Unquote(quoted)
+		line = line[len(quoted):]
+		if line != ":" && !strings.HasPrefix(line, ": ") {
+			return "", "", false
+		}
+		return key, strings.TrimPrefix(line[1:], " "), true
+	}
+	if first, _ := utf8.DecodeRuneInString(line); line == "" || unicode.IsSpace(first) {
+		return "", "", false
+	}
+	if key, value, ok = strings.Cut(line, ": "); ok {
+		return key, value, true
+	}
+	if strings.HasSuffix(line, ":") && len(line) > 1 {
+		return line[:len(line)-1], "", true
+	}
+	return "", "", false
+}
+
+// isTXTKeyLine indique si une ligne est de la forme "clé: valeur"
+func isTXTKeyLine(line string) bool {
+	_, _, ok := cutTXTKey(line)
+	return ok
+}
+
+// txtKey écrit une clé telle quelle si cutTXTKey la relit, entre guillemets sinon
+func txtKey(key string) string {
+	if key == "" || strings.ContainsAny(key, "\r\n") || strings.Contains(key, ": ") ||
+		strings.HasSuffix(key, ":") || strings.HasPrefix(key, `"`) {
+		return strconv.Quote(key)
+	}
+	if first, _ := utf8.DecodeRuneInString(key); unicode.IsSpace(first) {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// fixedReader lit un tableau à colonnes de largeur fixe. Les colonnes sont
+// données par les options, ou, avec header, déduites de l'en-tête et de son
+// soulignement ("----  ------"): chaque colonne s'étend jusqu'à la suivante.
+type fixedReader struct {
+	scanner *bufio.Scanner
+	columns []FixedColumn
+	header  bool
+	last    bool // La dernière colonne va jusqu'à la fin de la ligne
+}
+
+func (f *fixedReader) Columns() []string {
+	if f.header && f.columns == nil {
+		f.readHeader()
+	}
+	names := make([]string, len(f.columns))
+	for i, column := range f.columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// readHeader lit l'en-tête et son soulignement
+func (f *fixedReader) readHeader() {
+	f.columns = []FixedColumn{}
+	f.last = true
+	if !f.scanner.Scan() {
+		return
+	}
+	header := []rune(strings.TrimRight(f.scanner.Text(), "\r"))
+	if !f.scanner.Scan() {
+		return
+	}
+	rule := []rune(f.scanner.Text())
+
+	var starts []int
+	for i, r := range rule {
+		if r == '-' && (i == 0 || rule[i-1] != '-') {
+			starts = append(starts, i)
+		}
+	}
+	for i, start := range starts {
+		end := len(header)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		f.columns = append(f.columns, FixedColumn{
+			Name:  strings.TrimSpace(runeSlice(header, start, end)),
+			Width: end - start,
+		})
+	}
+}
+
+func (f *fixedReader) Next() (*Record, error) {
+	if f.header && f.columns == nil {
+		f.readHeader()
+	}
+	for f.scanner.Scan() {
+		line := []rune(strings.TrimRight(f.scanner.Text(), "\r"))
+		if strings.TrimSpace(string(line)) == "" {
+			continue
+		}
+		item := NewRecord()
+		start := 0
+		for i, column := range f.columns {
+			end := start + column.Width
+			if f.last && i == len(f.columns)-1 {
+				end = len(line)
+			}
+			item.Set(column.Name, strings.TrimSpace(runeSlice(line, start, end)))
+			start = end
+		}
+		return item, nil
+	}
+	if err := f.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture du texte: %v", err)
+	}
+	return nil, io.EOF
+}
+
+// runeSlice retourne les caractères [start:end) d'une ligne, bornés à sa longueur
+func runeSlice(line []rune, start, end int) string {
+	if end > len(line) {
+		end = len(line)
+	}
+	if start >= end {
+		return ""
+	}
+	return string(line[start:end])
+}
+
+func newTXTWriter(w io.Writer, opts TXTOptions, columns []string) recordWriter {
+	switch {
+	case len(opts.Widths) > 0:
+		return &tableWriter{w: w, widths: opts.Widths}
+	case opts.layout() == TXTTable:
+		return &tableWriter{w: w, table: Table{Columns: columns}}
+	}
+	return &txtWriter{w: w}
+}
+
+// txtWriter écrit un bloc "clé: valeur" par enregistrement. Les lignes
+// suivantes d'une valeur sur plusieurs lignes sont indentées.
+type txtWriter struct {
+	w io.Writer
+}
+
+func (t *txtWriter) Write(item *Record) error {
+	var builder strings.Builder
+	for _, key := range item.Keys() {
+		value, _ := item.Get(key)
+		text := txtNewlines.Replace(FormatValue(value))
+		builder.WriteString(fmt.Sprintf("%s: %s\n", txtKey(key), text))
+	}
+	builder.WriteString("\n")
+	_, err := io.WriteString(t.w, builder.String())
+	return err
+}
+
+func (t *txtWriter) Close() error {
+	return nil
+}
+
+// txtNewlines indente les lignes de suite; un \r seul compte aussi comme fin
+// de ligne, comme à la lecture
+var txtNewlines = strings.NewReplacer("\r\n", "\n"+txtContinuation, "\n", "\n"+txtContinuation, "\r", "\n"+txtContinuation)
+
+// tableWriter écrit un tableau aligné: un en-tête souligné puis une ligne
+// par enregistrement, les colonnes séparées par deux espaces. Les largeurs
+// dépendent de toutes les valeurs: les enregistrements sont gardés en
+// mémoire jusqu'à la fin. Avec des largeurs imposées, chaque ligne est
+// écrite aussitôt, sans en-tête, et les valeurs trop longues sont tronquées.
+type tableWriter struct {
+	w      io.Writer
+	table  Table
+	widths []FixedColumn
+}
+
+func (t *tableWriter) Write(item *Record) error {
+	if len(t.widths) == 0 {
+		t.table.Append(item)
+		return nil
+	}
+	var builder strings.Builder
+	for _, column := range t.widths {
+		value, _ := item.Get(column.Name)
+		cell := []rune(tableCell(value))
+		if len(cell) > column.Width {
+			cell = cell[:column.Width]
+		}
+		builder.WriteString(string(cell) + strings.Repeat(" ", column.Width-len(cell)))
+	}
+	builder.WriteString("\n")
+	_, err := io.WriteString(t.w, builder.String())
+	return err
+}
+
+func (t *tableWriter) Close() error {
+	if len(t.widths) > 0 {
+		return nil
+	}
+	if len(t.table.Columns) == 0 {
+		return fmt.Errorf("pas de données à convertir")
+	}
+
+	rows := make([][]string, 0, len(t.table.Records)+2)
+	widths := make([]int, len(t.table.Columns))
+	rows = append(rows, t.table.Columns, nil)
+	for _, item := range t.table.Records {
+		row := make([]string, len(t.table.Columns))
+		for i, column := range t.table.Columns {
+			value, _ := item.Get(column)
+			row[i] = tableCell(value)
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	rule := make([]string, len(widths))
+	for i, width := range widths {
+		// Une colonne vide garde un tiret pour rester repérable à la lecture
+		widths[i] = max(width, 1)
+		rule[i] = strings.Repeat("-", widths[i])
+	}
+	rows[1] = rule
+
+	var builder strings.Builder
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		builder.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	_, err := io.WriteString(t.w, builder.String())
+	return err
+}
+
+// tableCell retourne une valeur sur une seule ligne
+func tableCell(value interface{}) string {
+	return tableSpaces.Replace(FormatValue(value))
+}
+
+var tableSpaces = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTXTBlocksRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string // Enregistrements en JSON
+		want  string // Relecture attendue, input si vide
+	}{
+		{name: "simple", input: `[{"id":"1","nom":"Zoé"},{"id":"2","nom":""}]`},
+		{name: "clé avec deux-points", input: `[{"dc:title":"X","url":"http://a.b/c: d"}]`},
+		{name: "clés à échapper", input: `[{"a: b":"1","fin:":"2","":"3"," espace":"4","\"cité\"":"5","ligne\nsuite":"6"}]`},
+		{name: "valeurs sur plusieurs lignes", input: `[{"a":"un\ndeux","b":"\ndébut","c":"fin\n","d":"un\n\ndeux"}]`},
+		{name: "blancs conservés", input: `[{"a":"  retrait","b":"fin  ","c":" "}]`},
+		{name: "retours chariot", input: `[{"a":"un\r\ndeux","b":"un\rdeux\r"}]`,
+			want: `[{"a":"un\ndeux","b":"un\ndeux\n"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, got := roundTrip(t, tt.input, "txt")
+			expected := tt.want
+			if expected == "" {
+				expected = tt.input
+			}
+			var want interface{}
+			if err := json.Unmarshal([]byte(expected), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("relecture de %q = %v, attendu %v", text, got, want)
+			}
+		})
+	}
+}
+
+func TestLongLines(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{name: "lignes", format: "txt", input: long + "\n"},
+		{name: "blocs", format: "txt", input: "a: " + long + "\n\n"},
+		{name: "ini", format: "ini", input: "[s]\na = " + long + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := convertText(tt.input, ConvertOptions{InputFormat: tt.format, OutputFormat: "json"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, long) {
+				t.Errorf("ligne longue perdue")
+			}
+		})
+	}
+}
@@ -0,0 +1,598 @@
+// internal/converter/xml.go
+package converter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// xmlReader lit un document XML quelconque. Les éléments désignés par le
+// chemin XMLOptions.Path (par défaut les enfants de la racine) sont lus un
+// par un et donnent chacun un enregistrement:
+//   - les attributs deviennent des clés "@nom";
+//   - les éléments enfants deviennent des clés, une liste s'ils sont répétés;
+//   - le texte d'un élément sans attribut ni enfant devient sa valeur, sinon
+//     la clé "#text";
+//   - les noms gardent leur préfixe d'espace de noms ("dc:title").
+//
+// Les éléments <field name="..."> écrits par ce convertisseur sont relus
+// avec leur type.
+type xmlReader struct {
+	decoder  *xml.Decoder
+	opts     XMLOptions
+	path     xmlPath
+	stack    []string          // Noms des éléments ouverts, depuis la racine
+	prefixes map[string]string // Préfixe déclaré pour chaque espace de noms
+	started  bool
+}
+
+func newXMLReader(r io.Reader, opts XMLOptions) (*xmlReader, error) {
+	decoder := xml.NewDecoder(r)
+	// L'entrée est déjà décodée en UTF-8: l'encodage déclaré est ignoré
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return &xmlReader{
+		decoder:  decoder,
+		opts:     opts,
+		path:     parseXMLPath(opts.Path),
+		prefixes: make(map[string]string),
+	}, nil
+}
+
+func (x *xmlReader) Next() (*Record, error) {
+	for {
+		tok, err := x.decoder.Token()
+		if err == io.EOF {
+			if !x.started {
+				return nil, fmt.Errorf("erreur lors du parsing XML: %v", io.ErrUnexpectedEOF)
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors du parsing XML: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			x.declare(t)
+			name := x.qualify(t.Name)
+			if !x.started {
+				if x.opts.Root != "" && x.opts.Path == "" && !xmlNameMatch(x.opts.Root, name) {
+					return nil, fmt.Errorf("erreur lors du parsing XML: expected element type <%s> but have <%s>", x.opts.Root, name)
+				}
+				x.started = true
+			}
+			x.stack = append(x.stack, name)
+			if !x.isRecord() {
+				continue
+			}
+
+			node, err := x.readNode(t, name)
+			x.stack = x.stack[:len(x.stack)-1]
+			if err != nil {
+				return nil, fmt.Errorf("erreur lors du parsing XML: %v", err)
+			}
+			value, err := node.value()
+			if err != nil {
+				return nil, fmt.Errorf("erreur lors du parsing XML: %v", err)
+			}
+			return xmlRecord(value), nil
+		case xml.EndElement:
+			x.stack = x.stack[:len(x.stack)-1]
+		}
+	}
+}
+
+// isRecord indique si l'élément qui vient d'être ouvert est un enregistrement
+func (x *xmlReader) isRecord() bool {
+	if len(x.path.parts) > 0 {
+		return x.path.match(x.stack)
+	}
+	return len(x.stack) == 2 && (x.opts.Item == "" || xmlNameMatch(x.opts.Item, x.stack[1]))
+}
+
+// declare enregistre les espaces de noms déclarés par un élément
+func (x *xmlReader) declare(start xml.StartElement) {
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			x.prefixes[attr.Value] = attr.Name.Local
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			if _, ok := x.prefixes[attr.Value]; !ok {
+				x.prefixes[attr.Value] = ""
+			}
+		}
+	}
+}
+
+// qualify retourne le nom d'un élément ou d'un attribut avec son préfixe
+func (x *xmlReader) qualify(name xml.Name) string {
+	switch {
+	case name.Space == "":
+		return name.Local
+	case name.Space == xmlNamespace:
+		return "xml:" + name.Local
+	}
+	if prefix, ok := x.prefixes[name.Space]; ok {
+		if prefix == "" {
+			return name.Local
+		}
+		return prefix + ":" + name.Local
+	}
+	// Préfixe non déclaré: encoding/xml le laisse tel quel
+	return name.Space + ":" + name.Local
+}
+
+// Espace de noms du préfixe réservé "xml" (xml:lang...)
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// xmlNode est un élément XML lu en entier
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr // Noms qualifiés dans Name.Local
+	children []*xmlNode
+	text     strings.Builder // Texte direct, hors enfants
+}
+
+// readNode lit le contenu de l'élément start, jusqu'à sa fermeture
+func (x *xmlReader) readNode(start xml.StartElement, name string) (*xmlNode, error) {
+	node := &xmlNode{name: name}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		node.attrs = append(node.attrs, xml.Attr{Name: xml.Name{Local: x.qualify(attr.Name)}, Value: attr.Value})
+	}
+
+	for {
+		tok, err := x.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			x.declare(t)
+			child, err := x.readNode(t, x.qualify(t.Name))
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		case xml.CharData:
+			node.text.Write(t)
+		case xml.EndElement:
+			return node, nil
+		}
+	}
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// value convertit l'élément vers le modèle de Record
+func (n *xmlNode) value() (interface{}, error) {
+	if n.isFields() {
+		return n.fields()
+	}
+
+	record := NewRecord()
+	for _, attr := range n.attrs {
+		record.Set("@"+attr.Name.Local, attr.Value)
+	}
+	counts := make(map[string]int)
+	for _, child := range n.children {
+		counts[child.name]++
+	}
+	for _, child := range n.children {
+		value, err := child.value()
+		if err != nil {
+			return nil, err
+		}
+		if counts[child.name] == 1 {
+			record.Set(child.name, value)
+			continue
+		}
+		// Élément répété: une liste, à la place du premier
+		existing, _ := record.Get(child.name)
+		list, _ := existing.([]interface{})
+		record.Set(child.name, append(list, value))
+	}
+
+	text := n.text.String()
+	if record.Len() == 0 {
+		if strings.TrimSpace(text) == "" {
+			return "", nil
+		}
+		return text, nil
+	}
+	if trimmed := strings.TrimSpace(text); trimmed != "" {
+		record.Set("#text", trimmed)
+	}
+	return record, nil
+}
+
+// isFields reconnaît un élément qui ne contient que des <field name="...">
+func (n *xmlNode) isFields() bool {
+	if len(n.children) == 0 || len(n.attrs) > 0 || strings.TrimSpace(n.text.String()) != "" {
+		return false
+	}
+	for _, child := range n.children {
+		if child.name != "field" || child.attr("name") == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// fields lit les éléments <field> et leur attribut "type"
+func (n *xmlNode) fields() (*Record, error) {
+	record := NewRecord()
+	for _, child := range n.children {
+		if child.name != "field" {
+			continue
+		}
+		value, err := child.typedValue()
+		if err != nil {
+			return nil, err
+		}
+		record.Set(child.attr("name"), value)
+	}
+	return record, nil
+}
+
+// typedValue lit la valeur d'un élément selon son attribut "type": texte,
+// objet (éléments <field>) ou liste (éléments <value>)
+func (n *xmlNode) typedValue() (interface{}, error) {
+	kind := n.attr("type")
+	switch kind {
+	case "object":
+		return n.fields()
+	case "array":
+		list := []interface{}{}
+		for _, child := range n.children {
+			value, err := child.typedValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	}
+	return parseXMLValue(kind, n.text.String())
+}
+
+// xmlRecord fait un enregistrement de la valeur d'un élément: un élément
+// vide donne un enregistrement vide, un texte seul la clé "value"
+func xmlRecord(value interface{}) *Record {
+	if value == "" {
+		return NewRecord()
+	}
+	return jsonRecord(value)
+}
+
+// xmlPath désigne les éléments lus comme enregistrements: "catalog/book"
+// depuis la racine, "//book" à n'importe quelle profondeur. "*" désigne un
+// élément quelconque et un nom sans préfixe convient quel que soit l'espace
+// de noms.
+type xmlPath struct {
+	parts    []string
+	anywhere bool
+}
+
+func parseXMLPath(path string) xmlPath {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return xmlPath{}
+	}
+	anywhere := strings.HasPrefix(path, "//")
+	return xmlPath{parts: strings.Split(strings.Trim(path, "/"), "/"), anywhere: anywhere}
+}
+
+func (p xmlPath) match(stack []string) bool {
+	if len(stack) < len(p.parts) || (!p.anywhere && len(stack) != len(p.parts)) {
+		return false
+	}
+	stack = stack[len(stack)-len(p.parts):]
+	for i, part := range p.parts {
+		if !xmlNameMatch(part, stack[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// validate refuse les segments vides ("a//b") et les caractères interdits
+func (p xmlPath) validate() error {
+	for _, part := range p.parts {
+		if part == "" || strings.ContainsAny(part, " \t\r\n<>&\"'=") {
+			return fmt.Errorf("chemin XML invalide: segment %q", part)
+		}
+	}
+	return nil
+}
+
+// xmlNameMatch compare un nom de chemin ("book", "dc:title" ou "*") au nom
+// qualifié d'un élément
+func xmlNameMatch(pattern, name string) bool {
+	if pattern == "*" || pattern == name {
+		return true
+	}
+	if !strings.Contains(pattern, ":") {
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			return name[i+1:] == pattern
+		}
+	}
+	return false
+}
+
+// xmlWriter écrit <root> puis un élément <item> par enregistrement. Selon
+// XMLOptions.Style, les champs sont des éléments <field name="..."> typés,
+// des éléments nommés d'après les clés ou des attributs de <item>.
+type xmlWriter struct {
+	w        io.Writer
+	opts     XMLOptions
+	encoding string // Encodage de sortie, déclaré dans l'en-tête
+	encoder  *xml.Encoder
+}
+
+func (x *xmlWriter) rootElement() xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: x.opts.root()}}
+	prefixes := make([]string, 0, len(x.opts.Namespaces))
+	for prefix := range x.opts.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		name := "xmlns"
+		if prefix != "" {
+			name += ":" + prefix
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: x.opts.Namespaces[prefix]})
+	}
+	return start
+}
+
+func (x *xmlWriter) start() error {
+	if x.encoder != nil {
+		return nil
+	}
+	header := xml.Header
+	if x.encoding != "" {
+		header = fmt.Sprintf("<?xml version=\"1.0\" encoding=\"%s\"?>\n", x.encoding)
+	}
+	if _, err := io.WriteString(x.w, header); err != nil {
+		return err
+	}
+	x.encoder = xml.NewEncoder(x.w)
+	if indent := x.opts.indent(); indent != "" {
+		x.encoder.Indent("", indent)
+	}
+	return x.encoder.EncodeToken(x.rootElement())
+}
+
+func (x *xmlWriter) Write(item *Record) error {
+	if err := x.start(); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: x.opts.item()}}
+	if x.opts.style() != XMLFields {
+		if err := x.writeElement(start, item); err != nil {
+			return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+		}
+		return nil
+	}
+	if err := x.encoder.EncodeToken(start); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+	if err := x.writeFields(item); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+	if err := x.encoder.EncodeToken(start.End()); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+	return nil
+}
+
+// writeFields écrit un élément <field name="..."> par champ
+func (x *xmlWriter) writeFields(item *Record) error {
+	for _, key := range item.Keys() {
+		value, _ := item.Get(key)
+		start := xml.StartElement{
+			Name: xml.Name{Local: "field"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: key}},
+		}
+		if err := x.writeValue(start, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeValue écrit une valeur dans l'élément start; l'attribut "type"
+// indique les valeurs qui ne sont pas des chaînes
+func (x *xmlWriter) writeValue(start xml.StartElement, value interface{}) error {
+	if kind := xmlType(value); kind != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "type"}, Value: kind})
+	}
+	if err := x.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case *Record:
+		if err := x.writeFields(v); err != nil {
+			return err
+		}
+	case []interface{}:
+		for _, elem := range v {
+			if err := x.writeValue(xml.StartElement{Name: xml.Name{Local: "value"}}, elem); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := x.encoder.EncodeToken(xml.CharData(FormatValue(v))); err != nil {
+			return err
+		}
+	}
+	return x.encoder.EncodeToken(start.End())
+}
+
+// writeElement écrit une valeur dans l'élément start, en styles élément et
+// attribut. Comme à la lecture, les clés "@nom" sont des attributs et la clé
+// "#text" le texte de l'élément.
+func (x *xmlWriter) writeElement(start xml.StartElement, value interface{}) error {
+	record, ok := value.(*Record)
+	if !ok {
+		if err := x.encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if value != nil {
+			if err := x.encoder.EncodeToken(xml.CharData(FormatValue(value))); err != nil {
+				return err
+			}
+		}
+		return x.encoder.EncodeToken(start.End())
+	}
+
+	var attrs, attrKeys, children []string
+	var text interface{}
+	for _, key := range record.Keys() {
+		v, _ := record.Get(key)
+		switch {
+		case key == "#text":
+			text = v
+		case isNested(v):
+			children = append(children, key)
+		case strings.HasPrefix(key, "@"):
+			attrs, attrKeys = append(attrs, key[1:]), append(attrKeys, key)
+		case x.opts.style() == XMLAttributes:
+			attrs, attrKeys = append(attrs, key), append(attrKeys, key)
+		default:
+			children = append(children, key)
+		}
+	}
+
+	for i, name := range xmlNames(attrs) {
+		if v, _ := record.Get(attrKeys[i]); v != nil {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: FormatValue(v)})
+		}
+	}
+	if err := x.encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for i, name := range xmlNames(children) {
+		v, _ := record.Get(children[i])
+		if err := x.writeChild(xml.StartElement{Name: xml.Name{Local: name}}, v); err != nil {
+			return err
+		}
+	}
+	if text != nil {
+		if err := x.encoder.EncodeToken(xml.CharData(FormatValue(text))); err != nil {
+			return err
+		}
+	}
+	return x.encoder.EncodeToken(start.End())
+}
+
+// writeChild écrit un champ en élément; une liste donne un élément répété,
+// une liste dans une liste des éléments <value>
+func (x *xmlWriter) writeChild(start xml.StartElement, value interface{}) error {
+	list, ok := value.([]interface{})
+	if !ok {
+		return x.writeElement(start, value)
+	}
+	for _, elem := range list {
+		inner, ok := elem.([]interface{})
+		if !ok {
+			if err := x.writeElement(start, elem); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := x.encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if err := x.writeChild(xml.StartElement{Name: xml.Name{Local: "value"}}, inner); err != nil {
+			return err
+		}
+		if err := x.encoder.EncodeToken(start.End()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *xmlWriter) Close() error {
+	if err := x.start(); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+	if err := x.encoder.EncodeToken(x.rootElement().End()); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage XML: %v", err)
+	}
+	return x.encoder.Flush()
+}
+
+// xmlNames retourne des noms distincts pour les clés d'un même élément. Une
+// clé déjà valide garde son nom; une clé dont le nom corrigé est pris reçoit
+// un suffixe ("a b" → "a_b_2" à côté de "a_b").
+func xmlNames(keys []string) []string {
+	names := make([]string, len(keys))
+	used := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		if xmlName(key) == key {
+			names[i] = key
+			used[key] = true
+		}
+	}
+	for i, key := range keys {
+		if names[i] != "" {
+			continue
+		}
+		name := xmlName(key)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", xmlName(key), n)
+		}
+		names[i] = name
+		used[name] = true
+	}
+	return names
+}
+
+// xmlName rend une clé utilisable comme nom d'élément ou d'attribut: les
+// caractères interdits sont remplacés par "_", et un nom qui ne commence
+// pas par une lettre ou "_" est préfixé par "_" ("1er prix" → "_1er_prix")
+func xmlName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		case i > 0 && r == ':' && !strings.Contains(key[:i], ":") && i < len(key)-1:
+			// Préfixe d'espace de noms ("dc:title")
+		default:
+			if i == 0 && (r == '-' || r == '.' || unicode.IsDigit(r)) {
+				b.WriteByte('_')
+				b.WriteRune(r)
+				continue
+			}
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
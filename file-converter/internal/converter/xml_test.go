@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestXMLWriterNameCollisions(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		input string
+		want  []string // Extraits attendus dans la sortie
+	}{
+		{name: "attributs", style: XMLAttributes, input: `[{"a b":1,"a_b":2}]`,
+			want: []string{`a_b_2="1"`, `a_b="2"`}},
+		{name: "éléments", style: XMLElements, input: `[{"a b":1,"a_b":2}]`,
+			want: []string{"<a_b_2>1</a_b_2>", "<a_b>2</a_b>"}},
+		{name: "suffixe déjà pris", style: XMLElements, input: `[{"a b":1,"a_b":2,"a_b_2":3}]`,
+			want: []string{"<a_b_3>1</a_b_3>", "<a_b>2</a_b>", "<a_b_2>3</a_b_2>"}},
+		{name: "attribut @ et champ", style: XMLAttributes, input: `[{"@x y":1,"x_y":2}]`,
+			want: []string{`x_y_2="1"`, `x_y="2"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := convertText(tt.input, ConvertOptions{InputFormat: "json", OutputFormat: "xml", XML: XMLOptions{Style: tt.style}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("sortie sans %s:\n%s", want, out)
+				}
+			}
+
+			// La relecture retrouve autant de champs, sans liste
+			data, err := convertText(out, ConvertOptions{InputFormat: "xml", OutputFormat: "json", JSON: JSONOptions{Compact: true}})
+			if err != nil {
+				t.Fatalf("relecture: %v", err)
+			}
+			var records []map[string]interface{}
+			if err := json.Unmarshal([]byte(data), &records); err != nil {
+				t.Fatalf("relecture = %s: %v", data, err)
+			}
+			if len(records) != 1 || len(records[0]) != len(tt.want) {
+				t.Errorf("relecture = %s, %d champs attendus", data, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestXMLPath(t *testing.T) {
+	const catalog = `<catalog><book id="1"><title>A</title></book><shelf><book id="2"><title>B</title><tags><tag>x</tag><tag>y</tag></tags></book></shelf></catalog>`
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "chemin depuis la racine", input: catalog, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "catalog/book"}},
+			want: `[{"@id":"1","title":"A"}]`},
+		{name: "à toute profondeur", input: catalog, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "//book"}},
+			want: `[{"@id":"1","title":"A"},{"@id":"2","title":"B","tags":{"tag":["x","y"]}}]`},
+		{name: "joker", input: catalog, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "catalog/*/book"}},
+			want: `[{"@id":"2","title":"B","tags":{"tag":["x","y"]}}]`},
+		{name: "préfixes d'espace de noms", input: `<r xmlns:dc="urn:dc"><dc:i><dc:title>T</dc:title></dc:i></r>`, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "//i"}},
+			want: `[{"dc:title":"T"}]`},
+		{name: "contenu mixte", input: `<r><i><b>gras</b>texte</i><i a="1">2</i></r>`, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "r/i"}},
+			want: `[{"b":"gras","#text":"texte"},{"@a":"1","#text":"2"}]`},
+		{name: "aucun élément", input: catalog, opts: ConvertOptions{OutputFormat: "json", JSON: compact, XML: XMLOptions{Path: "catalog/dvd"}},
+			want: `[]`},
+		{name: "segment vide", input: catalog, opts: ConvertOptions{OutputFormat: "json", XML: XMLOptions{Path: "catalog//book"}},
+			wantErr: `chemin XML invalide: segment ""`},
+	})
+}
@@ -0,0 +1,243 @@
+// internal/converter/yaml.go
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlReader lit un flux YAML document par document. Une séquence donne un
+// enregistrement par élément, comme un tableau JSON; les autres documents
+// donnent un enregistrement chacun.
+type yamlReader struct {
+	decoder *yaml.Decoder
+	pending []interface{} // Éléments restants de la séquence en cours
+}
+
+func newYAMLReader(r io.Reader) *yamlReader {
+	return &yamlReader{decoder: yaml.NewDecoder(r)}
+}
+
+func (y *yamlReader) Next() (*Record, error) {
+	for len(y.pending) == 0 {
+		var doc yaml.Node
+		if err := y.decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("erreur lors du parsing YAML: %v", err)
+		}
+		value, err := yamlValue(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors du parsing YAML: %v", err)
+		}
+		if list, ok := value.([]interface{}); ok {
+			y.pending = list
+			continue
+		}
+		return jsonRecord(value), nil
+	}
+
+	value := y.pending[0]
+	y.pending = y.pending[1:]
+	return jsonRecord(value), nil
+}
+
+// yamlValue convertit un nœud YAML vers le modèle de Record, en gardant
+// l'ordre des clés
+func yamlValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := yamlValue(child)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		record := NewRecord()
+		if err := yamlMerge(record, node); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	return yamlScalar(node)
+}
+
+// yamlMerge ajoute les paires d'un mapping à record; les clés de fusion
+// ("<<: *defaut") n'écrasent pas les clés explicites
+func yamlMerge(record *Record, node *yaml.Node) error {
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			merged = append(merged, value)
+			continue
+		}
+		v, err := yamlValue(value)
+		if err != nil {
+			return err
+		}
+		record.Set(key.Value, v)
+	}
+
+	for _, value := range merged {
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			if source.Kind == yaml.AliasNode {
+				source = source.Alias
+			}
+			if source.Kind != yaml.MappingNode {
+				return fmt.Errorf("ligne %d: seul un mapping peut être fusionné", source.Line)
+			}
+			extra := NewRecord()
+			if err := yamlMerge(extra, source); err != nil {
+				return err
+			}
+			for _, key := range extra.Keys() {
+				if _, ok := record.Get(key); !ok {
+					v, _ := extra.Get(key)
+					record.Set(key, v)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// yamlScalar convertit un scalaire selon son type YAML
+func yamlScalar(node *yaml.Node) (interface{}, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case "!!int":
+		var n int64
+		if err := node.Decode(&n); err != nil {
+			// Entier trop grand pour int64: gardé tel quel s'il est décimal
+			if numberPattern.MatchString(node.Value) {
+				return json.Number(node.Value), nil
+			}
+			return node.Value, nil
+		}
+		return json.Number(strconv.FormatInt(n, 10)), nil
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return nil, err
+		}
+		// Infini et NaN n'existent pas en JSON
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return node.Value, nil
+		}
+		if numberPattern.MatchString(node.Value) {
+			return json.Number(node.Value), nil
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case "!!timestamp":
+		if date, ok := parseDate(node.Value); ok {
+			return date, nil
+		}
+	}
+	return node.Value, nil
+}
+
+// yamlWriter écrit les enregistrements comme une séquence YAML
+type yamlWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (y *yamlWriter) Write(item *Record) error {
+	// Chaque enregistrement est encodé comme une séquence d'un élément:
+	// les fragments mis bout à bout forment une seule séquence
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{yamlNode(item)}}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(seq); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage YAML: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("erreur lors de l'encodage YAML: %v", err)
+	}
+	y.count++
+	_, err := y.w.Write(buf.Bytes())
+	return err
+}
+
+func (y *yamlWriter) Close() error {
+	if y.count == 0 {
+		_, err := io.WriteString(y.w, "[]\n")
+		return err
+	}
+	return nil
+}
+
+// yamlNode construit le nœud YAML d'une valeur du modèle
+func yamlNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case *Record:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range v.Keys() {
+			child, _ := v.Get(key)
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				yamlNode(child))
+		}
+		if len(node.Content) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, child := range v {
+			node.Content = append(node.Content, yamlNode(child))
+		}
+		if len(node.Content) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		return node
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case Date:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: v.Text}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: FormatValue(value)}
+}
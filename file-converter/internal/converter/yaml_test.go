@@ -0,0 +1,19 @@
+package converter
+
+import "testing"
+
+func TestYAML(t *testing.T) {
+	compact := JSONOptions{Compact: true}
+	runTextTests(t, []textTest{
+		{name: "plusieurs documents", input: "a: 1\nb: x\n---\na: 2\n", opts: ConvertOptions{InputFormat: "yaml", OutputFormat: "json", JSON: compact},
+			want: `[{"a":1,"b":"x"},{"a":2}]`},
+		{name: "liste puis objet", input: "- a: 1\n- a: 2\n---\na: 3\n", opts: ConvertOptions{InputFormat: "yaml", OutputFormat: "json", JSON: compact},
+			want: `[{"a":1},{"a":2},{"a":3}]`},
+		{name: "ancres et types", input: "base: &b\n  x: 1\nc:\n  y: 2\n  <<: *b\nd: 2024-01-02\ne: ~\n", opts: ConvertOptions{InputFormat: "yaml", OutputFormat: "json", JSON: compact},
+			want: `[{"base":{"x":1},"c":{"y":2,"x":1},"d":"2024-01-02","e":null}]`},
+		{name: "écriture", input: `[{"a":1,"b":"x"},{"a":2,"b":"2024-01-02"}]`, opts: ConvertOptions{OutputFormat: "yaml"},
+			want: "- a: 1\n  b: x\n- a: 2\n  b: \"2024-01-02\"\n"},
+		{name: "syntaxe invalide", input: "a: [1\n", opts: ConvertOptions{InputFormat: "yaml", OutputFormat: "json"},
+			wantErr: "erreur lors du parsing YAML"},
+	})
+}
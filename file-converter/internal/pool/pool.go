@@ -0,0 +1,119 @@
+// internal/pool/pool.go
+package pool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Pool limite le nombre de tâches exécutées simultanément.
+// Un même pool peut être partagé entre plusieurs appelants (CLI, serveur HTTP)
+// pour ne pas surcharger le processeur.
+type Pool struct {
+	slots chan struct{}
+}
+
+// New crée un pool de size workers; size <= 0 utilise GOMAXPROCS
+func New(size int) *Pool {
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{slots: make(chan struct{}, size)}
+}
+
+// Size retourne le nombre maximal de tâches simultanées
+func (p *Pool) Size() int {
+	return cap(p.slots)
+}
+
+// Acquire réserve une place dans le pool, en attendant si nécessaire
+func (p *Pool) Acquire(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release libère une place réservée par Acquire
+func (p *Pool) Release() {
+	<-p.slots
+}
+
+// Do exécute fn dès qu'une place est disponible
+func (p *Pool) Do(ctx context.Context, fn func() error) error {
+	if err := p.Acquire(ctx); err != nil {
+		return err
+	}
+	defer p.Release()
+	return fn()
+}
+
+// Ordered exécute n tâches sur le pool et transmet leurs résultats à emit
+// dans l'ordre des indices, quel que soit l'ordre de terminaison.
+// Si stop retourne vrai pour un résultat, le contexte des tâches est annulé
+// et les tâches non démarrées sont abandonnées: emit n'est alors appelé
+// que pour les tâches lancées. Le nombre de résultats en attente d'émission
+// est borné pour limiter la mémoire utilisée.
+func Ordered[T any](ctx context.Context, p *Pool, n int,
+	task func(ctx context.Context, i int) T,
+	stop func(result T) bool,
+	emit func(i int, result T)) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexed struct {
+		i      int
+		result T
+	}
+	results := make(chan indexed)
+	// Fenêtre d'avance: une tâche ne démarre pas tant que trop de résultats attendent
+	window := make(chan struct{}, p.Size()*4)
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+		for i := 0; i < n; i++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			if err := p.Acquire(ctx); err != nil {
+				return
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				result := task(ctx, i)
+				p.Release()
+				results <- indexed{i: i, result: result}
+			}(i)
+		}
+	}()
+
+	pending := make(map[int]T)
+	next := 0
+	for res := range results {
+		if stop != nil && stop(res.result) {
+			cancel()
+		}
+		pending[res.i] = res.result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			emit(next, result)
+			<-window
+			next++
+		}
+	}
+}
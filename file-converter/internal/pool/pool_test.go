@@ -0,0 +1,142 @@
+package pool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		size int
+		want int
+	}{
+		{size: 3, want: 3},
+		{size: 0, want: runtime.GOMAXPROCS(0)},
+		{size: -1, want: runtime.GOMAXPROCS(0)},
+	}
+	for _, tt := range tests {
+		if got := New(tt.size).Size(); got != tt.want {
+			t.Errorf("New(%d).Size() = %d, attendu %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestPoolDo(t *testing.T) {
+	p := New(2)
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Do(context.Background(), func() error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("%d tâches simultanées, 2 au plus attendues", peak)
+	}
+}
+
+func TestPoolAcquireCancel(t *testing.T) {
+	p := New(1)
+	if err := p.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Acquire(ctx); err == nil {
+		t.Fatal("Acquire() sur un pool plein: erreur attendue à l'expiration du contexte")
+	}
+	called := false
+	if err := p.Do(ctx, func() error { called = true; return nil }); err == nil || called {
+		t.Errorf("Do() après expiration du contexte: erreur = %v, tâche exécutée = %v", err, called)
+	}
+	p.Release()
+	if err := p.Acquire(context.Background()); err != nil {
+		t.Errorf("place non libérée par Release: %v", err)
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		n      int
+		stopAt int // Résultat qui interrompt les tâches, -1 pour aucun
+	}{
+		{name: "aucune tâche", size: 2, n: 0, stopAt: -1},
+		{name: "une place", size: 1, n: 20, stopAt: -1},
+		{name: "plusieurs places", size: 4, n: 50, stopAt: -1},
+		{name: "arrêt", size: 1, n: 100, stopAt: 3},
+		{name: "arrêt en parallèle", size: 4, n: 100, stopAt: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var emitted []int
+			task := func(ctx context.Context, i int) int {
+				// Les premières tâches finissent en dernier
+				time.Sleep(time.Duration(tt.n-i) * 10 * time.Microsecond)
+				return i
+			}
+			stop := func(result int) bool { return result == tt.stopAt }
+			Ordered(context.Background(), New(tt.size), tt.n, task, stop, func(i int, result int) {
+				if i != result {
+					t.Errorf("emit(%d) avec le résultat de la tâche %d", i, result)
+				}
+				emitted = append(emitted, i)
+			})
+
+			for i, got := range emitted {
+				if got != i {
+					t.Fatalf("résultats émis dans le désordre: %v", emitted)
+				}
+			}
+			switch {
+			case tt.stopAt < 0 && len(emitted) != tt.n:
+				t.Errorf("%d résultats émis, attendu %d", len(emitted), tt.n)
+			case tt.stopAt >= 0 && (len(emitted) <= tt.stopAt || len(emitted) == tt.n):
+				t.Errorf("%d résultats émis après un arrêt au résultat %d", len(emitted), tt.stopAt)
+			}
+		})
+	}
+}
+
+func TestOrderedCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var started int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Ordered(ctx, New(2), 1000, func(ctx context.Context, i int) int {
+			if atomic.AddInt32(&started, 1) == 2 {
+				cancel()
+			}
+			<-ctx.Done()
+			return i
+		}, nil, func(int, int) {})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Ordered() ne s'arrête pas à l'annulation du contexte")
+	}
+	if n := atomic.LoadInt32(&started); n >= 1000 {
+		t.Errorf("%d tâches démarrées après l'annulation", n)
+	}
+}
@@ -0,0 +1,107 @@
+// internal/watcher/inotify_linux.go
+//go:build linux
+
+package watcher
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Événements surveillés: fin d'écriture, fichier déplacé dans le dossier,
+// création et modification (les écritures sont ensuite stabilisées par Settle)
+const notifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE | syscall.IN_MODIFY
+
+// newNotifyWatcher surveille dir avec inotify. Le descripteur est non bloquant
+// pour que Close interrompe la lecture en cours.
+func newNotifyWatcher(dir string) (*Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syscall.InotifyAddWatch(fd, dir, notifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	events := make(chan string)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+
+		// Les fichiers déjà présents sont signalés au démarrage
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, entry := range entries {
+				if !entry.Type().IsRegular() {
+					continue
+				}
+				select {
+				case events <- filepath.Join(dir, entry.Name()):
+				case <-done:
+					return
+				}
+			}
+		}
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				if !errors.Is(err, os.ErrClosed) {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+				return
+			}
+
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				mask := binary.NativeEndian.Uint32(buf[offset+4:])
+				nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+				start := offset + syscall.SizeofInotifyEvent
+				name := strings.TrimRight(string(buf[start:start+nameLen]), "\x00")
+				offset = start + nameLen
+
+				if mask&syscall.IN_Q_OVERFLOW != 0 {
+					select {
+					case errs <- errors.New("file d'événements inotify saturée"):
+					default:
+					}
+					continue
+				}
+				if mask&syscall.IN_ISDIR != 0 || name == "" {
+					continue
+				}
+				select {
+				case events <- filepath.Join(dir, name):
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return &Watcher{
+		Events: events,
+		Errors: errs,
+		Mode:   ModeNotify,
+		stop: func() error {
+			err := error(nil)
+			once.Do(func() {
+				close(done)
+				err = file.Close()
+			})
+			return err
+		},
+	}, nil
+}
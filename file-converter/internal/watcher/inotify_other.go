@@ -0,0 +1,11 @@
+// internal/watcher/inotify_other.go
+//go:build !linux
+
+package watcher
+
+import "errors"
+
+// newNotifyWatcher n'est disponible que sous Linux: la scrutation prend le relais
+func newNotifyWatcher(dir string) (*Watcher, error) {
+	return nil, errors.New("notifications non disponibles sur ce système")
+}
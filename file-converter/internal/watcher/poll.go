@@ -0,0 +1,79 @@
+// internal/watcher/poll.go
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// newPollWatcher compare le contenu du dossier à chaque intervalle
+func newPollWatcher(dir string, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	events := make(chan string)
+	errors := make(chan error, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+		type snapshot struct {
+			size int64
+			mod  time.Time
+		}
+		seen := make(map[string]snapshot)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				select {
+				case errors <- err:
+				default:
+				}
+			}
+
+			current := make(map[string]snapshot, len(entries))
+			for _, entry := range entries {
+				if !entry.Type().IsRegular() {
+					continue
+				}
+				info, err := entry.Info()
+				if err != nil {
+					continue
+				}
+				path := filepath.Join(dir, entry.Name())
+				snap := snapshot{size: info.Size(), mod: info.ModTime()}
+				current[path] = snap
+				if prev, ok := seen[path]; !ok || prev != snap {
+					select {
+					case events <- path:
+					case <-done:
+						return
+					}
+				}
+			}
+			seen = current
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return &Watcher{
+		Events: events,
+		Errors: errors,
+		Mode:   ModePolling,
+		stop: func() error {
+			once.Do(func() { close(done) })
+			return nil
+		},
+	}
+}
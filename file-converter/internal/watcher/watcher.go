@@ -0,0 +1,117 @@
+// internal/watcher/watcher.go
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Modes de surveillance
+const (
+	ModeNotify  = "inotify"
+	ModePolling = "polling"
+)
+
+// Watcher signale les fichiers créés ou modifiés dans un dossier (non récursif)
+type Watcher struct {
+	Events <-chan string // Chemins des fichiers créés ou modifiés
+	Errors <-chan error  // Erreurs de surveillance
+	Mode   string        // ModeNotify ou ModePolling
+
+	stop func() error
+}
+
+// New surveille dir avec inotify sous Linux et se replie sur une scrutation
+// toutes les interval lorsque les notifications ne sont pas disponibles
+func New(dir string, interval time.Duration) (*Watcher, error) {
+	if _, err := os.ReadDir(dir); err != nil {
+		return nil, err
+	}
+	if w, err := newNotifyWatcher(dir); err == nil {
+		return w, nil
+	}
+	return newPollWatcher(dir, interval), nil
+}
+
+// NewPolling surveille dir uniquement par scrutation périodique
+func NewPolling(dir string, interval time.Duration) (*Watcher, error) {
+	if _, err := os.ReadDir(dir); err != nil {
+		return nil, err
+	}
+	return newPollWatcher(dir, interval), nil
+}
+
+// Close arrête la surveillance
+func (w *Watcher) Close() error {
+	return w.stop()
+}
+
+// Ignored indique si un fichier doit être ignoré: fichiers cachés et
+// fichiers temporaires d'un transfert en cours
+func Ignored(path string) bool {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tmp", ".part", ".partial", ".crdownload", ".swp":
+		return true
+	}
+	return false
+}
+
+// Settle transmet à ready les fichiers reçus sur events une fois que leur
+// taille et leur date de modification n'ont pas changé pendant delay, pour ne
+// pas traiter un fichier en cours d'écriture. Settle rend la main à l'arrêt du
+// contexte ou à la fermeture de events.
+func Settle(ctx context.Context, events <-chan string, delay time.Duration, ready func(path string)) {
+	type pending struct {
+		size  int64
+		mod   time.Time
+		since time.Time
+	}
+	files := make(map[string]*pending)
+
+	tick := delay / 4
+	if tick < 10*time.Millisecond {
+		tick = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case path, ok := <-events:
+			if !ok {
+				return
+			}
+			stat, err := os.Stat(path)
+			if err != nil || !stat.Mode().IsRegular() {
+				delete(files, path)
+				continue
+			}
+			files[path] = &pending{size: stat.Size(), mod: stat.ModTime(), since: time.Now()}
+		case now := <-ticker.C:
+			for path, p := range files {
+				stat, err := os.Stat(path)
+				if err != nil {
+					delete(files, path)
+					continue
+				}
+				if stat.Size() != p.size || !stat.ModTime().Equal(p.mod) {
+					p.size, p.mod, p.since = stat.Size(), stat.ModTime(), now
+					continue
+				}
+				if now.Sub(p.since) >= delay {
+					delete(files, path)
+					ready(path)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "in/data.json", want: false},
+		{path: "in/.data.json", want: true},
+		{path: "in/data.json~", want: true},
+		{path: "in/data.json.part", want: true},
+		{path: "in/data.TMP", want: true},
+		{path: "in/film.crdownload", want: true},
+		{path: "in/notes.txt", want: false},
+	}
+	for _, tt := range tests {
+		if got := Ignored(tt.path); got != tt.want {
+			t.Errorf("Ignored(%s) = %v, attendu %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// waitEvent attend un événement pour path, en ignorant les autres
+func waitEvent(t *testing.T, w *Watcher, path string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case got, ok := <-w.Events:
+			if !ok {
+				t.Fatalf("événements fermés avant %s", path)
+			}
+			if got == path {
+				return
+			}
+		case err := <-w.Errors:
+			t.Fatalf("erreur de surveillance: %v", err)
+		case <-timeout:
+			t.Fatalf("pas d'événement pour %s", path)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	tests := []struct {
+		name  string
+		start func(dir string) (*Watcher, error)
+	}{
+		{name: "notifications", start: func(dir string) (*Watcher, error) { return New(dir, 10*time.Millisecond) }},
+		{name: "scrutation", start: func(dir string) (*Watcher, error) { return NewPolling(dir, 10*time.Millisecond) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			existing := filepath.Join(dir, "a.json")
+			if err := os.WriteFile(existing, []byte("[]"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(filepath.Join(dir, "archive"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := tt.start(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Logf("mode %s", w.Mode)
+
+			// Fichier présent au démarrage, puis fichier créé et fichier modifié
+			waitEvent(t, w, existing)
+			created := filepath.Join(dir, "b.json")
+			if err := os.WriteFile(created, []byte("[]"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, created)
+			if err := os.WriteFile(existing, []byte("[1, 2]"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, existing)
+
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("deuxième Close(): %v", err)
+			}
+			timeout := time.After(5 * time.Second)
+			for {
+				select {
+				case path, ok := <-w.Events:
+					if !ok {
+						return
+					}
+					if path == filepath.Join(dir, "archive") {
+						t.Errorf("dossier signalé: %s", path)
+					}
+				case <-timeout:
+					t.Fatal("événements non fermés après Close()")
+				}
+			}
+		})
+	}
+}
+
+func TestWatcherMissingDir(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "absent")
+	if _, err := New(missing, time.Second); err == nil {
+		t.Error("New() sur un dossier absent: erreur attendue")
+	}
+	if _, err := NewPolling(missing, time.Second); err == nil {
+		t.Error("NewPolling() sur un dossier absent: erreur attendue")
+	}
+}
+
+func TestSettle(t *testing.T) {
+	dir := t.TempDir()
+	stable := filepath.Join(dir, "stable.json")
+	removed := filepath.Join(dir, "removed.json")
+	for _, path := range []string{stable, removed} {
+		if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events := make(chan string)
+	ready := make(chan string, 2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Settle(context.Background(), events, 50*time.Millisecond, func(path string) { ready <- path })
+	}()
+	events <- stable
+	events <- removed
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case path := <-ready:
+		if path != stable {
+			t.Errorf("fichier prêt: %s, attendu %s", path, stable)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fichier stable jamais transmis")
+	}
+
+	close(events)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Settle() ne rend pas la main à la fermeture des événements")
+	}
+	select {
+	case path := <-ready:
+		t.Errorf("fichier supprimé transmis: %s", path)
+	default:
+	}
+}